@@ -64,6 +64,6 @@ Tag:
 */
 package testapi
 
-func test() {
+func testModel() {
 
 }
@@ -21,7 +21,7 @@
             400:
                 Description: Invalid ID supplied
             404:
-                Description: Pet not found
+                "$ref": "#/responses/NotFound"
         Security:
             - api_key: []
         Summary: Find pet by ID
@@ -30,6 +30,47 @@
 */
 package testapi
 
-func test() {
+func testRoute() {
 
 }
+
+// api:route
+// "/store/inventory":
+//
+//	Get:
+//	    Description: Returns a map of status codes to quantities
+//	    OperationId: getInventory
+//	    Parameters:
+//	        - $ref: "#/parameters/limit"
+//	    Produces:
+//	        - application/json
+//	    Responses:
+//	        200:
+//	            Description: successful operation
+//	    Security:
+//	        - api_key: []
+//	    Summary: Returns pet inventories by status
+//	    Tags:
+//	        - store
+func getInventory() {
+
+}
+
+/* api:response
+NotFound:
+    Description: Entity not found
+*/
+
+/* api:parameter
+limit:
+    Description: Maximum number of items to return
+    Format: int32
+    In: query
+    Name: limit
+    Type: integer
+*/
+
+/* api:securityDefinition
+basic_auth:
+    Type: basic
+*/
@@ -0,0 +1,30 @@
+package testapi
+
+import "time"
+
+// api:model
+// An order placed for a pet in the store
+type Order struct {
+	ID       int64     `json:"id,omitempty"`
+	PetID    int64     `json:"petId,omitempty"`
+	Quantity int32     `json:"quantity,omitempty"`
+	ShipDate time.Time `json:"shipDate,omitempty"`
+	// Order Status
+	Status   OrderStatus `json:"status,omitempty"`
+	Complete bool        `json:"complete"`
+	Customer *Customer   `json:"customer,omitempty"`
+}
+
+type OrderStatus string
+
+type Customer struct {
+	Username string            `json:"username"`
+	Address  []Address         `json:"address,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+type Address struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+	Zip    string `json:"zip"` // postal code
+}
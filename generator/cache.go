@@ -0,0 +1,303 @@
+package generator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// markupCache is the markup of a project saved between runs
+// Files holds the markup comments of every file read, which only depend on the file itself. Packages holds the
+// routes and models of every package, which come from type information across files, packages and modules.
+type markupCache struct {
+	Version  string
+	Files    map[string]*cachedFile
+	Packages map[string]*cachedPackage
+}
+
+// cachedFile is the markup of a file, valid as long as the file has the same size and modification time,
+// or failing that the same content hash
+type cachedFile struct {
+	ModTime   time.Time
+	Size      int64
+	Hash      string
+	Generated bool
+	Comments  []comment
+}
+
+// cachedPackage is the routes and models of a package, valid as long as the package has the same hash
+type cachedPackage struct {
+	Hash       string
+	Extraction *packageExtraction
+}
+
+// cacheListing is the state of the project found by refreshing the cache
+// files are the selected files in the order they are read, cached counts those whose markup was in the cache
+// and changed tells whether the files of the cache must be written back.
+type cacheListing struct {
+	files    []string
+	packages []*listedPackage
+	cached   int
+	changed  bool
+}
+
+// listedPackage is a package of the project as listed by refreshing the cache
+// the hash covers the build tags, the include and exclude globs, the files of the package, the go.mod and go.sum of its module and the hashes
+// of the packages of the main modules it imports. deps are the packages of the project it imports, directly or not.
+type listedPackage struct {
+	path    string
+	hash    string
+	goFiles []string
+	deps    []string
+}
+
+// cachePath returns the file the cache of the project in opts.Dir is saved to
+func (opts Options) cachePath() string {
+	dir, err := filepath.Abs(opts.Dir)
+	if err != nil {
+		dir = opts.Dir
+	}
+	sum := sha256.Sum256([]byte(dir))
+	return filepath.Join(opts.CacheDir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// readCache reads the cache saved at path
+// a missing or unreadable cache, or one written by another version of swagson, is replaced by an empty one
+func readCache(path string) *markupCache {
+	var cache markupCache
+	b, err := os.ReadFile(path)
+	if err != nil || json.Unmarshal(b, &cache) != nil || cache.Version != VERSION {
+		cache = markupCache{Version: VERSION}
+	}
+	if cache.Files == nil {
+		cache.Files = make(map[string]*cachedFile)
+	}
+	return &cache
+}
+
+// write saves the cache at path
+// the cache is written to a temporary file renamed over the previous one, so concurrent runs never read half a cache
+func (c *markupCache) write(path string) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(b)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// refresh lists the packages of the project and brings the cache entries of their files up to date
+// unchanged files are recognized by their size and modification time, or by their content hash after a touch,
+// the others are parsed again for their comments. Entries of files that are gone are dropped. The files of the
+// packages of the main modules the project imports are tracked too, since the routes and models depend on them.
+func (c *markupCache) refresh(ctx context.Context, opts Options) (*cacheListing, error) {
+	pkgs, err := listPackages(ctx, opts.Dir, opts.Package, opts.Tags)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := filepath.Abs(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var listing = &cacheListing{}
+	var files = make(map[string]*cachedFile)
+	var unchanged = make(map[string]bool)
+	var modules = make(map[string]string)
+	var hashes = make(map[string]string)
+	var hash func(p *packages.Package) (string, error)
+	hash = func(p *packages.Package) (string, error) {
+		if h, ok := hashes[p.ID]; ok {
+			return h, nil
+		}
+		h := sha256.New()
+		// routes and models only come from the files selected by the globs
+		fmt.Fprintf(h, "tags %s\n", strings.Join(opts.Tags, ","))
+		fmt.Fprintf(h, "include %q\nexclude %q\n", opts.Include, opts.Exclude)
+		for _, name := range slices.Sorted(slices.Values(p.GoFiles)) {
+			entry, cached, err := c.lookup(name)
+			if err != nil {
+				return "", err
+			}
+			files[name], unchanged[name] = entry, cached
+			if entry != c.Files[name] {
+				listing.changed = true
+			}
+			fmt.Fprintf(h, "%s %s\n", name, entry.Hash)
+		}
+		if p.Module != nil && p.Module.GoMod != "" {
+			if _, ok := modules[p.Module.GoMod]; !ok {
+				modules[p.Module.GoMod] = moduleHash(p.Module.GoMod)
+			}
+			fmt.Fprintf(h, "%s %s\n", p.Module.GoMod, modules[p.Module.GoMod])
+		}
+		for _, path := range sortedKeys(p.Imports) {
+			imp := p.Imports[path]
+			if imp.Module == nil || !imp.Module.Main {
+				continue
+			}
+			dep, err := hash(imp)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(h, "import %s %s\n", path, dep)
+		}
+		hashes[p.ID] = hex.EncodeToString(h.Sum(nil))
+		return hashes[p.ID], nil
+	}
+
+	var listed = make(map[string]bool)
+	for _, p := range pkgs {
+		listed[p.PkgPath] = true
+	}
+	for _, p := range pkgs {
+		key, err := hash(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range p.GoFiles {
+			selected, err := opts.selectFile(dir, name)
+			if err != nil {
+				return nil, err
+			}
+			if !selected || files[name].Generated {
+				continue
+			}
+			if unchanged[name] {
+				listing.cached++
+			}
+			listing.files = append(listing.files, name)
+		}
+		listing.packages = append(listing.packages, &listedPackage{
+			path:    p.PkgPath,
+			hash:    key,
+			goFiles: p.GoFiles,
+			deps:    projectDeps(p, listed, make(map[string]bool)),
+		})
+	}
+	if len(files) != len(c.Files) {
+		listing.changed = true
+	}
+	c.Files = files
+	return listing, nil
+}
+
+// projectDeps returns the packages of the project imported by p, directly or through other packages of its main modules
+func projectDeps(p *packages.Package, listed map[string]bool, seen map[string]bool) []string {
+	var deps []string
+	for _, path := range sortedKeys(p.Imports) {
+		imp := p.Imports[path]
+		if seen[imp.ID] || imp.Module == nil || !imp.Module.Main {
+			continue
+		}
+		seen[imp.ID] = true
+		if listed[imp.PkgPath] {
+			deps = append(deps, imp.PkgPath)
+		}
+		deps = append(deps, projectDeps(imp, listed, seen)...)
+	}
+	return deps
+}
+
+// stale returns the packages whose routes and models must be loaded again, those missing from the cache or whose
+// hash changed
+func (c *markupCache) stale(listing *cacheListing) map[string]bool {
+	var stale = make(map[string]bool)
+	for _, p := range listing.packages {
+		if entry := c.Packages[p.path]; entry == nil || entry.Extraction == nil || entry.Hash != p.hash {
+			stale[p.path] = true
+		}
+	}
+	return stale
+}
+
+// moduleHash hashes the go.mod of a module along with its go.sum, which pin the modules its packages import
+func moduleHash(gomod string) string {
+	h := sha256.New()
+	for _, name := range []string{gomod, filepath.Join(filepath.Dir(gomod), "go.sum")} {
+		if hash, err := hashFile(name); err == nil {
+			fmt.Fprintf(h, "%s %s\n", name, hash)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// lookup returns the cache entry of the file at name, reporting whether its markup was already cached
+func (c *markupCache) lookup(name string) (*cachedFile, bool, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, false, err
+	}
+	entry := c.Files[name]
+	if entry != nil && entry.Size == info.Size() && entry.ModTime.Equal(info.ModTime()) {
+		return entry, true, nil
+	}
+
+	src, err := os.ReadFile(name)
+	if err != nil {
+		return nil, false, err
+	}
+	sum := sha256.Sum256(src)
+	hash := hex.EncodeToString(sum[:])
+	if entry != nil && entry.Hash == hash {
+		updated := *entry
+		updated.ModTime, updated.Size = info.ModTime(), info.Size()
+		return &updated, true, nil
+	}
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, name, src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, false, err
+	}
+	return &cachedFile{
+		ModTime:   info.ModTime(),
+		Size:      info.Size(),
+		Hash:      hash,
+		Generated: ast.IsGenerated(f),
+		Comments:  *extractComments(fset, f),
+	}, false, nil
+}
+
+// hashFile returns the hex encoded sha256 hash of the content of a file
+func hashFile(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
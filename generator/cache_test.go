@@ -0,0 +1,182 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Build_cache(t *testing.T) {
+	var stats Stats
+	opts := Options{Dir: "../examples", CacheDir: t.TempDir(), Stats: &stats}
+	uncached, err := Generate(context.Background(), Options{Dir: "../examples"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := DocToJson(uncached)
+
+	for i, cached := range []int{0, 4} {
+		swagDoc, err := Generate(context.Background(), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		j, _ := DocToJson(swagDoc)
+		if string(*j) == string(*expected) && stats.Files == 4 && stats.Cached == cached {
+			t.Logf("Build(examples, cache, run %d) passed.", i+1)
+		} else {
+			t.Log(stats)
+			t.Errorf("Build(examples, cache, run %d) failed.", i+1)
+		}
+	}
+}
+
+func Test_markupCache_packages(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, src string) {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pet := "package models\n\n// Pet api:model\ntype Pet struct {\n\t%s string `json:\"%s\"`\n}\n"
+	write("go.mod", "module example.com/api\n\ngo 1.22\n")
+	write("api.go", "package api\n\nimport \"example.com/api/models\"\n\n/* api:meta\nSwagger: \"2.0\"\nInfo:\n    Title: pets\n    Version: \"1.0.0\"\nPaths: {}\n*/\n\nvar _ models.Pet\n")
+	write("handlers.go", "package api\n")
+	write("models/pet.go", fmt.Sprintf(pet, "Name", "name"))
+	write("store/order.go", "package store\n\n// Order api:model\ntype Order struct {\n\tID int `json:\"id\"`\n}\n")
+
+	var stats Stats
+	opts := Options{Dir: dir, CacheDir: t.TempDir(), Stats: &stats}
+	var tests = []struct {
+		name     string
+		file     string
+		src      string
+		stale    []string
+		cached   int
+		property string
+	}{
+		{"first run", "", "", []string{"example.com/api", "example.com/api/models", "example.com/api/store"}, 0, "name"},
+		{"unchanged", "", "", nil, 4, "name"},
+		{"edited package", "handlers.go", "package api\n\n// handlers\n", []string{"example.com/api"}, 3, "name"},
+		{"edited dependency", "models/pet.go", fmt.Sprintf(pet, "Tag", "tag"), []string{"example.com/api", "example.com/api/models"}, 3, "tag"},
+		{"edited other package", "store/order.go", "package store\n", []string{"example.com/api/store"}, 3, "tag"},
+	}
+	for _, test := range tests {
+		if test.file != "" {
+			write(test.file, test.src)
+		}
+		cache := readCache(opts.cachePath())
+		listing, err := cache.refresh(context.Background(), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stale := sortedKeys(cache.stale(listing))
+		swagDoc, err := Generate(context.Background(), opts)
+		if err == nil && strings.Join(stale, ",") == strings.Join(test.stale, ",") && stats.Files == 4 && stats.Cached == test.cached &&
+			(*(*swagDoc.Definitions)["Pet"].Properties)[test.property].Type == "string" {
+			t.Logf("Build(cache, %s) passed.", test.name)
+		} else {
+			t.Log(err, stale, stats)
+			t.Errorf("Build(cache, %s) failed.", test.name)
+		}
+	}
+}
+
+func Test_markupCache_globs(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, src string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("go.mod", "module example.com/api\n\ngo 1.22\n")
+	write("api.go", "package api\n\n/* api:meta\nSwagger: \"2.0\"\nInfo:\n    Title: pets\n    Version: \"1.0.0\"\nPaths: {}\n*/\n")
+	write("m.go", "package api\n\n// Pet api:model\ntype Pet struct {\n\tName string `json:\"name\"`\n}\n")
+
+	cacheDir := t.TempDir()
+	var tests = []struct {
+		name    string
+		exclude []string
+		pet     bool
+	}{
+		{"excluded", []string{"m.go"}, false},
+		{"included", nil, true},
+		{"excluded again", []string{"m.go"}, false},
+	}
+	for _, test := range tests {
+		swagDoc, err := Generate(context.Background(), Options{Dir: dir, CacheDir: cacheDir, Exclude: test.exclude})
+		var pet bool
+		if err == nil && swagDoc.Definitions != nil {
+			_, pet = (*swagDoc.Definitions)["Pet"]
+		}
+		if err == nil && pet == test.pet {
+			t.Logf("Build(cache, %s) passed.", test.name)
+		} else {
+			t.Log(err)
+			t.Errorf("Build(cache, %s) failed.", test.name)
+		}
+	}
+}
+
+func Test_markupCache_refresh(t *testing.T) {
+	dir := t.TempDir()
+	api := filepath.Join(dir, "api.go")
+	write := func(title string, mtime time.Time) {
+		src := "package api\n\n/* api:meta\nSwagger: \"2.0\"\nInfo:\n    Title: " + title + "\n    Version: \"1.0.0\"\nPaths: {}\n*/\n"
+		if err := os.WriteFile(api, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(api, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/api\n\ngo 1.22\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stats Stats
+	opts := Options{Dir: dir, CacheDir: t.TempDir(), Stats: &stats}
+	mtime := time.Now().Add(-time.Hour)
+	var tests = []struct {
+		name   string
+		title  string
+		mtime  time.Time
+		cached int
+	}{
+		{"first run", "pets", mtime, 0},
+		{"unchanged", "pets", mtime, 1},
+		{"touched", "pets", mtime.Add(time.Minute), 1},
+		{"edited", "stores", mtime.Add(time.Minute), 0},
+		{"edited again", "pets", mtime.Add(2 * time.Minute), 0},
+	}
+	for _, test := range tests {
+		write(test.title, test.mtime)
+		swagDoc, err := Generate(context.Background(), opts)
+		if err == nil && swagDoc.Info.Title == test.title && stats.Cached == test.cached {
+			t.Logf("Build(cache, %s) passed.", test.name)
+		} else {
+			t.Log(err, stats)
+			t.Errorf("Build(cache, %s) failed.", test.name)
+		}
+	}
+
+	path := opts.cachePath()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Replace(string(b), `"Version":"`+VERSION+`"`, `"Version":"0.0.0"`, 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if cache := readCache(path); len(cache.Files) == 0 && len(cache.Packages) == 0 && cache.Version == VERSION {
+		t.Log("readCache(other version) passed.")
+	} else {
+		t.Error("readCache(other version) failed.")
+	}
+}
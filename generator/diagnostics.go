@@ -0,0 +1,209 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Severity is the level of a diagnostic
+type Severity int
+
+const (
+	ERROR Severity = iota
+	WARNING
+)
+
+var severities = [...]string{"error", "warning"}
+
+func (s Severity) String() string {
+	return severities[s]
+}
+
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// errorList collects every error of a pass so they can be reported together instead of stopping at the first
+type errorList []error
+
+func (l errorList) Error() string {
+	var messages []string
+	for _, err := range l {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// add appends err to the list, flattening nested lists; nil errors are ignored
+func (l *errorList) add(err error) {
+	switch e := err.(type) {
+	case nil:
+	case errorList:
+		*l = append(*l, e...)
+	default:
+		*l = append(*l, err)
+	}
+}
+
+// err returns the list as an error, or nil when it is empty
+func (l errorList) err() error {
+	if len(l) == 0 {
+		return nil
+	}
+	return l
+}
+
+// Diagnostic is a single problem found during a run
+// File, Line and Column are set for problems in go source, Pointer for problems in the generated document
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Pointer  string   `json:"pointer,omitempty"`
+	Node     string   `json:"node,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// String formats the diagnostic as file.go:12:3: error: api:route: message
+func (d Diagnostic) String() string {
+	var parts []string
+	if d.File != "" {
+		parts = append(parts, displayPosition(token.Position{Filename: d.File, Line: d.Line, Column: d.Column}))
+	}
+	if d.Pointer != "" {
+		parts = append(parts, d.Pointer)
+	}
+	parts = append(parts, d.Severity.String())
+	if d.Node != "" {
+		parts = append(parts, d.Node)
+	}
+	parts = append(parts, d.Message)
+	return strings.Join(parts, ": ")
+}
+
+// Diagnostics collects the diagnostics of a whole run
+type Diagnostics []Diagnostic
+
+// error adds err as an error diagnostic
+func (d *Diagnostics) error(err error) {
+	d.add(ERROR, err)
+}
+
+// warning adds err as a warning diagnostic
+func (d *Diagnostics) warning(err error) {
+	d.add(WARNING, err)
+}
+
+// add converts err into one diagnostic per error it holds, keeping any position information
+func (d *Diagnostics) add(severity Severity, err error) {
+	switch e := err.(type) {
+	case nil:
+	case errorList:
+		for _, err := range e {
+			d.add(severity, err)
+		}
+	case *markupError:
+		*d = append(*d, Diagnostic{
+			Severity: severity,
+			File:     e.Pos.Filename,
+			Line:     e.Pos.Line,
+			Column:   e.Pos.Column,
+			Node:     e.Node.String(),
+			Message:  e.Msg,
+		})
+	case validationError:
+		*d = append(*d, Diagnostic{Severity: severity, Pointer: e.Pointer, Message: e.Message})
+	case packages.Error:
+		pos := parsePosition(e.Pos)
+		*d = append(*d, Diagnostic{
+			Severity: severity,
+			File:     pos.Filename,
+			Line:     pos.Line,
+			Column:   pos.Column,
+			Message:  e.Msg,
+		})
+	default:
+		*d = append(*d, Diagnostic{Severity: severity, Message: err.Error()})
+	}
+}
+
+// HasErrors reports whether any diagnostic is an error
+func (d Diagnostics) HasErrors() bool {
+	for _, diag := range d {
+		if diag.Severity == ERROR {
+			return true
+		}
+	}
+	return false
+}
+
+// Err returns the diagnostics as an error if any of them is an error, nil otherwise
+func (d Diagnostics) Err() error {
+	if !d.HasErrors() {
+		return nil
+	}
+	return d
+}
+
+// Error joins the text form of every diagnostic, one per line
+func (d Diagnostics) Error() string {
+	var lines []string
+	for _, diag := range d {
+		lines = append(lines, diag.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Print writes the diagnostics as text, one per line, or as a json array when format is json
+func (d Diagnostics) Print(w io.Writer, format string) error {
+	if format == "json" {
+		if d == nil {
+			d = Diagnostics{}
+		}
+		j, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(j))
+		return err
+	}
+	for _, diag := range d {
+		if _, err := fmt.Fprintln(w, diag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parsePosition parses a file:line:column position as printed by the go tool
+func parsePosition(pos string) token.Position {
+	var position token.Position
+	if pos == "" || pos == "-" {
+		return position
+	}
+	var numbers []int
+	for len(numbers) < 2 {
+		i := strings.LastIndex(pos, ":")
+		n, err := strconv.Atoi(pos[i+1:])
+		if i < 0 || err != nil {
+			break
+		}
+		numbers = append([]int{n}, numbers...)
+		pos = pos[:i]
+	}
+	position.Filename = pos
+	if len(numbers) > 0 {
+		position.Line = numbers[0]
+	}
+	if len(numbers) > 1 {
+		position.Column = numbers[1]
+	}
+	return position
+}
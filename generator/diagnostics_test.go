@@ -0,0 +1,91 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"go/token"
+	"testing"
+
+	"github.com/sfodje/swagson/specs"
+	"golang.org/x/tools/go/packages"
+)
+
+func Test_errorList(t *testing.T) {
+	var errs errorList
+	errs.add(nil)
+	if errs.err() == nil {
+		t.Log("errorList.err(empty) passed.")
+	} else {
+		t.Error("errorList.err(empty) failed.")
+	}
+
+	errs.add(errors.New("first"))
+	errs.add(errorList{errors.New("second"), errors.New("third")})
+	if len(errs) == 3 && errs.err().Error() == "first\nsecond\nthird" {
+		t.Log("errorList.add(nested list) passed.")
+	} else {
+		t.Log(errs)
+		t.Error("errorList.add(nested list) failed.")
+	}
+}
+
+func Test_diagnostics(t *testing.T) {
+	var diags Diagnostics
+	diags.error(errorList{
+		&markupError{Pos: token.Position{Filename: "pet.go", Line: 14, Column: 3}, Node: specs.APIROUTE, Msg: "yaml: did not find expected key"},
+		validationError{Pointer: "/info", Message: "Invalid type. Expected: object, given: null"},
+	})
+	diags.warning(packages.Error{Pos: "models.go:7:2", Msg: "undefined: Category", Kind: packages.TypeError})
+	if !diags.HasErrors() || len(diags) != 3 {
+		t.Fatal(diags)
+	}
+
+	var buf bytes.Buffer
+	expected := "pet.go:14:3: error: api:route: yaml: did not find expected key\n" +
+		"/info: error: Invalid type. Expected: object, given: null\n" +
+		"models.go:7:2: warning: undefined: Category\n"
+	if err := diags.Print(&buf, "text"); err == nil && buf.String() == expected {
+		t.Log("diagnostics.Print(text) passed.")
+	} else {
+		t.Log(buf.String())
+		t.Error("diagnostics.Print(text) failed.")
+	}
+
+	buf.Reset()
+	if err := diags[2:].Print(&buf, "json"); err == nil && buf.String() == `[
+  {
+    "severity": "warning",
+    "file": "models.go",
+    "line": 7,
+    "column": 2,
+    "message": "undefined: Category"
+  }
+]
+` {
+		t.Log("diagnostics.Print(json) passed.")
+	} else {
+		t.Log(buf.String())
+		t.Error("diagnostics.Print(json) failed.")
+	}
+
+	if diags[2:].HasErrors() {
+		t.Error("diagnostics.HasErrors(warnings) failed.")
+	}
+}
+
+func Test_parsePosition(t *testing.T) {
+	var tests = map[string]token.Position{
+		"/src/pet.go:12:5":  {Filename: "/src/pet.go", Line: 12, Column: 5},
+		"pet.go:12":         {Filename: "pet.go", Line: 12},
+		"C:/src/pet.go:3:1": {Filename: "C:/src/pet.go", Line: 3, Column: 1},
+		"-":                 {},
+	}
+	for pos, expected := range tests {
+		if actual := parsePosition(pos); actual == expected {
+			t.Logf("parsePosition(%q) passed.", pos)
+		} else {
+			t.Log(actual)
+			t.Errorf("parsePosition(%q) failed.", pos)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sfodje/swagson/specs"
+	"golang.org/x/tools/go/packages"
+)
+
+// extraction is what a document is assembled from: the markup comments of the selected files,
+// the route registrations found in them and the definitions generated from their api:model struct types
+// positions and types hold the declaration and qualified name of the struct type behind every definition.
+type extraction struct {
+	markup    map[specs.MarkupNode][]markupDoc
+	routes    []route
+	models    map[string]specs.SwagSchema
+	positions map[string]token.Position
+	types     map[string]string
+}
+
+func newExtraction() *extraction {
+	return &extraction{
+		markup:    make(map[specs.MarkupNode][]markupDoc),
+		models:    make(map[string]specs.SwagSchema),
+		positions: make(map[string]token.Position),
+		types:     make(map[string]string),
+	}
+}
+
+// packageExtraction is what the type information of a package gives: its route registrations and the definitions
+// generated from its api:model struct types, along with the declarations and qualified names of the types behind them
+type packageExtraction struct {
+	Routes    []route
+	Models    map[string]specs.SwagSchema
+	Positions map[string]token.Position
+	Types     map[string]string
+}
+
+// merge adds the routes and definitions of a package to the extraction
+// packages may share definitions generated from the same type, a definition generated from another struct type
+// of the same name is an error naming both declarations.
+func (ex *extraction) merge(p *packageExtraction) error {
+	var errs errorList
+	ex.routes = append(ex.routes, p.Routes...)
+	for _, name := range sortedKeys(p.Models) {
+		if prev, ok := ex.types[name]; ok {
+			if prev != p.Types[name] {
+				errs.add(definitionCollision(name, p.Types[name], p.Positions[name], prev, ex.positions[name]))
+			}
+			continue
+		}
+		ex.models[name] = p.Models[name]
+		ex.positions[name] = p.Positions[name]
+		ex.types[name] = p.Types[name]
+	}
+	return errs.err()
+}
+
+// extract reads the markup, routes and models of the project, from the cache in opts.CacheDir when it is set.
+// The markup of unchanged files is read from the cache and only the files that changed are parsed again. Routes
+// and models come from type information, only the packages whose files or dependencies changed are loaded again.
+// A cache that cannot be read or written only costs a full load.
+func (opts Options) extract(ctx context.Context, stats *Stats, diags *Diagnostics) (*extraction, error) {
+	if opts.CacheDir == "" {
+		return opts.load(ctx, stats, diags)
+	}
+
+	cache := readCache(opts.cachePath())
+	start := time.Now()
+	listing, err := cache.refresh(ctx, opts)
+	stats.Load = time.Since(start)
+	if err != nil {
+		// the listing fails on package errors, which a full load reports
+		listed := stats.Load
+		ex, err := opts.load(ctx, stats, diags)
+		stats.Load += listed
+		return ex, err
+	}
+
+	var stale = cache.stale(listing)
+	var loaded = make(map[string]*extractedPackage)
+	var clean = true
+	if len(stale) > 0 {
+		start = time.Now()
+		// the packages of the project imported by a stale package are loaded along with it for the docs of their fields
+		var patterns []string
+		var required = make(map[string]bool)
+		for _, p := range listing.packages {
+			if !stale[p.path] {
+				continue
+			}
+			for _, path := range append([]string{p.path}, p.deps...) {
+				if !required[path] {
+					required[path] = true
+					patterns = append(patterns, path)
+				}
+			}
+		}
+		before := len(*diags)
+		pkgs, err := loadPatterns(ctx, opts.Dir, opts.Package, opts.Tags, patterns, diags)
+		stats.Load += time.Since(start)
+		if err != nil {
+			return nil, err
+		}
+		start = time.Now()
+		extracted, err := opts.extractPackages(ctx, pkgs, stale, stats.Workers, diags)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range extracted {
+			loaded[e.path] = e
+		}
+		stats.Extract = time.Since(start)
+		// only packages loaded without any diagnostic are cached, so that no diagnostic is lost on the next run
+		clean = len(*diags) == before
+	}
+
+	start = time.Now()
+	var ex = newExtraction()
+	for _, name := range listing.files {
+		extractMarkup(ex.markup, &cache.Files[name].Comments)
+	}
+	var packages = make(map[string]*cachedPackage)
+	var changed = listing.changed || len(cache.Packages) != len(listing.packages)
+	for _, p := range listing.packages {
+		entry := cache.Packages[p.path]
+		if stale[p.path] {
+			e := loaded[p.path]
+			if e == nil {
+				changed = true
+				continue
+			}
+			entry = &cachedPackage{Hash: p.hash, Extraction: e.types}
+			// an entry without hash is used for this run and loaded again on the next one
+			if !clean || !slices.Equal(e.goFiles, p.goFiles) {
+				entry.Hash = ""
+			}
+			changed = true
+		}
+		packages[p.path] = entry
+		diags.error(ex.merge(entry.Extraction))
+	}
+	cache.Packages = packages
+	stats.Packages, stats.Files, stats.Cached = len(listing.packages), len(listing.files), listing.cached
+	for _, docs := range ex.markup {
+		stats.Comments += len(docs)
+	}
+	stats.Extract += time.Since(start)
+	if changed {
+		opts.writeCache(cache, diags)
+	}
+	return ex, nil
+}
+
+// load loads the project with type information and extracts its markup, routes and models
+func (opts Options) load(ctx context.Context, stats *Stats, diags *Diagnostics) (*extraction, error) {
+	start := time.Now()
+	pkgs, err := loadPackages(ctx, opts.Dir, opts.Package, opts.Tags, diags)
+	stats.Load = time.Since(start)
+	if err != nil {
+		return nil, err
+	}
+	stats.Packages = len(pkgs)
+
+	start = time.Now()
+	extracted, err := opts.extractPackages(ctx, pkgs, nil, stats.Workers, diags)
+	if err != nil {
+		return nil, err
+	}
+	var ex = newExtraction()
+	for _, e := range extracted {
+		for _, comments := range e.comments {
+			extractMarkup(ex.markup, comments)
+		}
+		diags.error(ex.merge(e.types))
+		stats.Files += len(e.comments)
+	}
+	for _, docs := range ex.markup {
+		stats.Comments += len(docs)
+	}
+	stats.Extract = time.Since(start)
+	return ex, nil
+}
+
+// extractedPackage is what is extracted from a package loaded with type information
+// comments holds the comments of every selected file in the order the files are read, goFiles the files of the package.
+type extractedPackage struct {
+	pkg      *packages.Package
+	path     string
+	goFiles  []string
+	comments []*[]comment
+	types    *packageExtraction
+}
+
+// extractPackages extracts the markup, routes and models of the loaded packages whose path is in paths, or of every
+// package when paths is nil. The other packages lend the doc comments of their struct fields to the models.
+func (opts Options) extractPackages(ctx context.Context, pkgs []*packages.Package, paths map[string]bool, workers int, diags *Diagnostics) ([]*extractedPackage, error) {
+	var registry = newTypeRegistry()
+	var extracted []*extractedPackage
+	var selected []packageFile
+	var owners []*extractedPackage
+	for _, p := range pkgs {
+		files, err := opts.selectFiles(p)
+		if err != nil {
+			return nil, err
+		}
+		registry.addPackage(p, files)
+		if paths != nil && !paths[p.PkgPath] {
+			continue
+		}
+		e := &extractedPackage{pkg: p, path: p.PkgPath, goFiles: slices.Sorted(slices.Values(p.GoFiles)), types: &packageExtraction{}}
+		for _, f := range files {
+			selected = append(selected, packageFile{p, f})
+			owners = append(owners, e)
+		}
+		extracted = append(extracted, e)
+	}
+
+	results, err := extractFiles(ctx, selected, workers)
+	if err != nil {
+		return nil, err
+	}
+	for i, result := range results {
+		owners[i].comments = append(owners[i].comments, result.comments)
+		owners[i].types.Routes = append(owners[i].types.Routes, result.routes...)
+	}
+	for _, e := range extracted {
+		models, objects, err := registry.schemas(registry.marked(e.pkg.Types))
+		diags.error(err)
+		e.types.Models = models
+		e.types.Positions = make(map[string]token.Position)
+		e.types.Types = make(map[string]string)
+		for name, obj := range objects {
+			e.types.Positions[name] = registry.position(obj)
+			e.types.Types[name] = qualifiedName(obj)
+		}
+	}
+	return extracted, nil
+}
+
+// writeCache saves the cache, failing to do so is reported as a warning since the document is not affected
+func (opts Options) writeCache(cache *markupCache, diags *Diagnostics) {
+	if err := cache.write(opts.cachePath()); err != nil {
+		diags.warning(fmt.Errorf("the markup cache could not be written: %v", err))
+	}
+}
+
+// packageFile is a selected file along with the package it was loaded in
+type packageFile struct {
+	pkg  *packages.Package
+	file *ast.File
+}
+
+// fileMarkup holds what is extracted from a single file
+type fileMarkup struct {
+	comments *[]comment
+	routes   []route
+}
+
+// extractFiles extracts the comments and route registrations of every file with a bounded pool of workers
+// go/packages parsed the files into one shared FileSet, the workers only read the syntax trees and type information.
+// Results are returned in the order of files whatever order the workers finish in, so the document assembled
+// from them does not depend on scheduling.
+func extractFiles(ctx context.Context, files []packageFile, workers int) ([]fileMarkup, error) {
+	var results = make([]fileMarkup, len(files))
+	var prefixes = make(map[*packages.Package]*routerPrefixes)
+	for _, f := range files {
+		if prefixes[f.pkg] == nil {
+			prefixes[f.pkg] = newRouterPrefixes(f.pkg)
+		}
+	}
+	var indexes = make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				f := files[i]
+				results[i] = fileMarkup{
+					comments: extractComments(f.pkg.Fset, f.file),
+					routes:   findRoutes(f.pkg, []*ast.File{f.file}, prefixes[f.pkg]),
+				}
+			}
+		}()
+	}
+
+	var err error
+	for i := range files {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// workers returns the number of workers extracting markup, one per available CPU unless set in the options
+func (opts Options) workers() int {
+	if opts.Workers > 0 {
+		return opts.Workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
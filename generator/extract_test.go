@@ -0,0 +1,55 @@
+package generator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func Test_extractFiles(t *testing.T) {
+	pkgs, err := loadPackages(context.Background(), "../examples/", "", nil, &Diagnostics{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files []packageFile
+	for _, p := range pkgs {
+		for _, f := range p.Syntax {
+			files = append(files, packageFile{p, f})
+		}
+	}
+
+	sequential, err := extractFiles(context.Background(), files, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	concurrent, err := extractFiles(context.Background(), files, 8)
+	if err == nil && len(concurrent) == len(files) && reflect.DeepEqual(sequential, concurrent) {
+		t.Log("extractFiles(files, 8 workers) passed.")
+	} else {
+		t.Log(err, len(concurrent))
+		t.Error("extractFiles(files, 8 workers) failed.")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := extractFiles(ctx, files, 4); err == context.Canceled {
+		t.Log("extractFiles(canceled) passed.")
+	} else {
+		t.Log(err)
+		t.Error("extractFiles(canceled) failed.")
+	}
+}
+
+func Test_Build_stats(t *testing.T) {
+	var stats Stats
+	_, diags := Build(context.Background(), Options{Dir: "../examples", Workers: 3, Stats: &stats})
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	if stats.Packages == 1 && stats.Files == 4 && stats.Comments > 0 && stats.Workers == 3 && stats.Total() > 0 {
+		t.Log("Build(stats) passed.")
+	} else {
+		t.Log(stats)
+		t.Error("Build(stats) failed.")
+	}
+}
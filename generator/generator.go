@@ -0,0 +1,466 @@
+// Package generator builds swagger documents from the markup comments and model types of a go project.
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go/ast"
+	"go/token"
+	"strings"
+	"time"
+
+	"github.com/sfodje/swagson/specs"
+)
+
+// VERSION is the version of swagson, markup cached by another version is discarded
+const VERSION = "0.0.1"
+
+// array of markup nodes to parse for
+var NODES = []specs.MarkupNode{specs.APIMETA, specs.APIROUTE, specs.APIMODEL, specs.APIPARAMETER, specs.APIRESPONSE, specs.APISECURITYDEFINITION}
+
+// comment is a block of go-style comments along with the position it starts at
+// Handler is the name of the function the comment documents, if any
+type comment struct {
+	Text    string
+	Pos     token.Position
+	Handler string
+}
+
+// extractComments returns a pointer to an array of all go-style comments in the given file
+// every /* */ comment is returned on its own while consecutive // comments of a comment group
+// are joined into a single block. Doc comments of api:model struct types are left to the type registry.
+func extractComments(fset *token.FileSet, f *ast.File) *[]comment {
+	var comments []comment
+	var models = modelDocs(f)
+	var handlers = funcDocs(f)
+	for _, cpkg := range f.Comments {
+		if models[cpkg] {
+			continue
+		}
+		handler := handlers[cpkg]
+		var lines []string
+		var start token.Pos
+		for _, c := range cpkg.List {
+			if strings.HasPrefix(c.Text, "/*") {
+				if len(lines) > 0 {
+					comments = append(comments, comment{strings.Join(lines, "\n"), fset.Position(start), handler})
+					lines = nil
+				}
+				comments = append(comments, comment{c.Text, fset.Position(c.Pos()), handler})
+				continue
+			}
+			if len(lines) == 0 {
+				start = c.Pos()
+			}
+			lines = append(lines, c.Text)
+		}
+		if len(lines) > 0 {
+			comments = append(comments, comment{strings.Join(lines, "\n"), fset.Position(start), handler})
+		}
+	}
+	return &comments
+}
+
+// funcDocs maps the doc comment of every function declared in the file to the function name
+func funcDocs(f *ast.File) map[*ast.CommentGroup]string {
+	var docs = make(map[*ast.CommentGroup]string)
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Doc != nil {
+			docs[fn.Doc] = fn.Name.Name
+		}
+	}
+	return docs
+}
+
+// commentLines splits a comment into lines without its comment delimiters
+// the opening /* and closing */ are dropped along with a line of their own, // prefixes are
+// stripped together with a single following space so the indentation of the yaml body is kept.
+// gofmt turns the indented lines of a // doc comment into a code block prefixed by a tab,
+// that tab is read back as one level of indentation.
+func commentLines(comment string) []string {
+	lines := strings.Split(comment, "\n")
+	if strings.HasPrefix(comment, "/*") {
+		last := len(lines) - 1
+		lines[0] = strings.TrimPrefix(lines[0], "/*")
+		lines[last] = strings.TrimSuffix(lines[last], "*/")
+		if last > 0 && strings.TrimSpace(lines[last]) == "" {
+			lines = lines[:last]
+		}
+		return lines
+	}
+	for i, line := range lines {
+		line = strings.TrimPrefix(line, "//")
+		if strings.HasPrefix(line, "\t") {
+			lines[i] = "    " + line[1:]
+		} else {
+			lines[i] = strings.TrimPrefix(line, " ")
+		}
+	}
+	return lines
+}
+
+// extractMarkup extracts comment text for each markup and populates the markup parameter with the data
+// It checks the first line of each comment block for any matching markup parameters (api:meta, api:route)
+// If found it links the markup name to the body of the comment in the markup map
+func extractMarkup(markup map[specs.MarkupNode][]markupDoc, comments *[]comment) {
+	for _, c := range *comments {
+		lines := commentLines(c.Text)
+		// swagson comments must have a body below the markup name
+		if len(lines) < 2 {
+			continue
+		}
+		f_line := strings.ToLower(lines[0])
+		for _, node := range NODES {
+			if strings.Contains(f_line, strings.ToLower(node.String())) {
+				comment_str := strings.Join(lines[1:], "\n")
+				markup[node] = append(markup[node], markupDoc{
+					Node:    node,
+					File:    c.Pos.Filename,
+					Line:    c.Pos.Line + 1,
+					Column:  c.Pos.Column,
+					Text:    comment_str,
+					Handler: c.Handler,
+				})
+			}
+		}
+	}
+}
+
+// extractSwaggerDoc extracts swagger doc data from the markup parameter,
+// populates a specs.SwagDoc struct and returns a pointer to it.
+// Every markup comment is processed even when some fail, the returned error holds all failures.
+func extractSwaggerDoc(markup *map[specs.MarkupNode][]markupDoc) (*specs.SwagDoc, error) {
+	var swagDoc = new(specs.SwagDoc)
+	var errs errorList
+	for _, node := range NODES {
+		docs := (*markup)[node]
+		if len(docs) == 0 {
+			continue
+		}
+		switch node {
+		default:
+		case specs.APIMETA:
+			errs.add(handleMeta(swagDoc, docs))
+		case specs.APIROUTE:
+			errs.add(handleRoute(swagDoc, docs))
+		case specs.APIMODEL:
+			errs.add(handleModel(swagDoc, docs))
+		case specs.APIPARAMETER:
+			errs.add(mergeNamed(&swagDoc.Parameters, docs, "parameter"))
+		case specs.APIRESPONSE:
+			errs.add(mergeNamed(&swagDoc.Responses, docs, "response"))
+		case specs.APISECURITYDEFINITION:
+			errs.add(mergeNamed(&swagDoc.SecurityDefinitions, docs, "security definition"))
+		}
+	}
+	return swagDoc, errs.err()
+}
+
+// handleRoute extracts data from the api:route markup
+// every api:route comment is merged into a single paths object. Operations declared
+// for the same path in different comments are merged into one path item, while
+// declaring the same path and verb twice is an error naming both source locations. Path-level parameters
+// may be repeated across comments as long as every declaration of a parameter is the same,
+// vendor extensions beside the paths may only be declared once.
+func handleRoute(swagDoc *specs.SwagDoc, docs []markupDoc) error {
+	var paths = specs.SwagPaths{Paths: make(map[string]specs.SwagPath)}
+	var sources = make(map[string]markupDoc)
+	var params = make(map[string]declaredParam)
+	var errs errorList
+	for _, doc := range docs {
+		docPaths, err := routePaths(doc)
+		if err != nil {
+			errs.add(err)
+			continue
+		}
+		for _, ext := range docPaths.Extensions {
+			key := "extension " + ext.Key
+			if prev, ok := sources[key]; ok {
+				errs.add(doc.errorf("%s is already declared at %s", ext.Key, displayPosition(prev.position(0))))
+				continue
+			}
+			sources[key] = doc
+			paths.Extensions = append(paths.Extensions, ext)
+		}
+		for _, name := range sortedKeys(docPaths.Paths) {
+			path := docPaths.Paths[name]
+			merged := paths.Paths[name]
+			if path.Parameters != nil {
+				for _, param := range *path.Parameters {
+					key := name + " " + paramKey(param)
+					prev, ok := params[key]
+					if !ok {
+						params[key] = declaredParam{doc, param}
+					} else if !sameParam(prev.param, param) {
+						errs.add(doc.errorf("parameter %s of %s is declared differently at %s", paramKey(param), name, displayPosition(prev.doc.position(0))))
+					}
+				}
+			}
+			for _, verb := range mergePath(&merged, &path) {
+				key := verb + " " + name
+				if prev, ok := sources[key]; ok {
+					errs.add(doc.errorf("%s %s is already declared at %s", strings.ToUpper(verb), name, displayPosition(prev.position(0))))
+					continue
+				}
+				sources[key] = doc
+			}
+			paths.Paths[name] = merged
+		}
+	}
+	swagDoc.Paths = &paths
+	return errs.err()
+}
+
+// mergePath copies the operations declared in src into dst and returns the verbs found in src
+// an operation already present in dst is left untouched; callers use the returned verbs to detect duplicates.
+// Parameters are identified by their $ref or their location and name, the ones already in dst are kept.
+func mergePath(dst *specs.SwagPath, src *specs.SwagPath) []string {
+	var verbs []string
+	var ops = []struct {
+		verb string
+		dst  **specs.SwagOperation
+		src  *specs.SwagOperation
+	}{
+		{"get", &dst.Get, src.Get},
+		{"put", &dst.Put, src.Put},
+		{"post", &dst.Post, src.Post},
+		{"delete", &dst.Delete, src.Delete},
+		{"options", &dst.Options, src.Options},
+		{"head", &dst.Head, src.Head},
+		{"patch", &dst.Patch, src.Patch},
+	}
+	for _, op := range ops {
+		if op.src == nil {
+			continue
+		}
+		if *op.dst == nil {
+			*op.dst = op.src
+		}
+		verbs = append(verbs, op.verb)
+	}
+	if dst.Ref == "" {
+		dst.Ref = src.Ref
+	}
+	if src.Parameters != nil {
+		var params []specs.SwagParam
+		var keys = make(map[string]bool)
+		if dst.Parameters != nil {
+			params = *dst.Parameters
+		}
+		for _, param := range params {
+			keys[paramKey(param)] = true
+		}
+		for _, param := range *src.Parameters {
+			if !keys[paramKey(param)] {
+				keys[paramKey(param)] = true
+				params = append(params, param)
+			}
+		}
+		dst.Parameters = &params
+	}
+	for _, ext := range src.Extensions {
+		if _, ok := dst.Extensions.Get(ext.Key); !ok {
+			dst.Extensions = append(dst.Extensions, ext)
+		}
+	}
+	return verbs
+}
+
+// declaredParam is a path-level parameter along with the markup declaring it
+type declaredParam struct {
+	doc   markupDoc
+	param specs.SwagParam
+}
+
+// paramKey identifies a parameter within a parameter list, by its $ref or else by its location and name
+func paramKey(param specs.SwagParam) string {
+	if param.Ref != "" {
+		return param.Ref
+	}
+	return param.In + " " + param.Name
+}
+
+// sameParam reports whether two parameters declare the same thing
+func sameParam(a specs.SwagParam, b specs.SwagParam) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
+
+// handleModel extracts data from the api:model markup
+// declaring the same definition in two comments is an error naming both source locations
+func handleModel(swagDoc *specs.SwagDoc, docs []markupDoc) error {
+	return mergeNamed(&swagDoc.Definitions, docs, "definition")
+}
+
+// mergeNamed decodes every doc into a map of named objects and adds them to the top-level map dst,
+// used by the nodes that fill the definitions, parameters, responses and securityDefinitions of the document.
+// Declaring a name twice is an error naming both source locations, names already in dst come from api:meta.
+func mergeNamed[T any](dst **map[string]T, docs []markupDoc, kind string) error {
+	if *dst == nil {
+		*dst = &map[string]T{}
+	}
+	var sources = make(map[string]markupDoc)
+	var errs errorList
+	for _, doc := range docs {
+		var named map[string]T
+		err := doc.decode(&named)
+		if err != nil {
+			errs.add(err)
+			continue
+		}
+		for _, k := range sortedKeys(named) {
+			v := named[k]
+			if prev, ok := sources[k]; ok {
+				errs.add(doc.errorf("%s %s is already declared at %s", kind, k, displayPosition(prev.position(0))))
+				continue
+			}
+			if _, ok := (**dst)[k]; ok {
+				errs.add(doc.errorf("%s %s is already declared in %s", kind, k, specs.APIMETA))
+				continue
+			}
+			sources[k] = doc
+			(**dst)[k] = v
+		}
+	}
+	return errs.err()
+}
+
+// mergeModels adds the definitions generated from go struct types to the document
+// a struct type may not share its name with a definition declared in api:model markup,
+// positions locate the type declarations for the error.
+func mergeModels(swagDoc *specs.SwagDoc, docs []markupDoc, models map[string]specs.SwagSchema, positions map[string]token.Position) error {
+	if len(models) == 0 {
+		return nil
+	}
+	if swagDoc.Definitions == nil {
+		swagDoc.Definitions = &map[string]specs.SwagSchema{}
+	}
+	var errs errorList
+	for _, name := range sortedKeys(models) {
+		schema := models[name]
+		if _, ok := (*swagDoc.Definitions)[name]; ok {
+			for _, doc := range docs {
+				var model map[string]interface{}
+				if doc.decode(&model) == nil && model[name] != nil {
+					errs.add(doc.errorf("definition %s is also declared by the struct type at %s", name, displayPosition(positions[name])))
+				}
+			}
+			continue
+		}
+		(*swagDoc.Definitions)[name] = schema
+	}
+	return errs.err()
+}
+
+// DocToJson converts a swagger or openapi document to json and returns a pointer
+// fields are written in the order of the specification (swagger, info, host, basePath, ...) with the verbs of a
+// path item in spec order, the keys of paths, definitions and the other maps sorted, and vendor extensions in
+// the order they were declared. The same document is written byte for byte the same on every run.
+func DocToJson(doc interface{}) (*[]byte, error) {
+	j, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &j, err
+}
+
+// DocToYaml converts a swagger or openapi document to yaml and returns a pointer
+// the yaml keeps the key order of the json document
+func DocToYaml(doc interface{}) (*[]byte, error) {
+	j, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	y, err := jsonToYAML(j)
+	if err != nil {
+		return nil, err
+	}
+
+	return &y, err
+}
+
+// Build loads the go project in opts.Dir and assembles a swagger document from its markup and model types
+// every problem found along the way is collected in the returned diagnostics, unless opts.SkipValidation is set
+// the document is also checked against the swagger 2.0 schema. The document is nil if the project could not be loaded.
+func Build(ctx context.Context, opts Options) (*specs.SwagDoc, Diagnostics) {
+	var diags Diagnostics
+	var stats = opts.Stats
+	if stats == nil {
+		stats = new(Stats)
+	}
+	*stats = Stats{Workers: opts.workers()}
+
+	ex, err := opts.extract(ctx, stats, &diags)
+	if err != nil {
+		diags.error(err)
+		return nil, diags
+	}
+	markup, routes := ex.markup, ex.routes
+
+	start := time.Now()
+	for _, w := range bindRoutes(markup[specs.APIROUTE], routes) {
+		diags.warning(w)
+	}
+	for _, node := range NODES {
+		for _, doc := range markup[node] {
+			if opts.Strict {
+				diags.error(checkKeys(doc))
+			} else {
+				diags.warning(checkKeys(doc))
+			}
+		}
+	}
+
+	swagDoc, err := extractSwaggerDoc(&markup)
+	diags.error(err)
+	diags.error(mergeModels(swagDoc, markup[specs.APIMODEL], ex.models, ex.positions))
+
+	warnings, err := checkPathParams(swagDoc, markup[specs.APIROUTE])
+	diags.error(err)
+	for _, w := range warnings {
+		diags.warning(w)
+	}
+
+	refErrors, err := checkRefs(swagDoc, markup)
+	diags.error(err)
+	for _, e := range refErrors {
+		diags.error(e)
+	}
+
+	if swagDoc.Swagger == "" {
+		diags.error(errors.New("Missing required property: 'swagger'"))
+	}
+	if swagDoc.Info == nil {
+		diags.error(errors.New("Missing required property: 'info'"))
+	}
+	if swagDoc.Paths == nil {
+		diags.error(errors.New("Missing required property: 'paths'"))
+	}
+
+	stats.Assemble = time.Since(start)
+
+	if !opts.SkipValidation {
+		start = time.Now()
+		violations, err := validateSwagDoc(swagDoc)
+		diags.error(err)
+		for _, v := range violations {
+			diags.error(v)
+		}
+		stats.Validate = time.Since(start)
+	}
+	return swagDoc, diags
+}
+
+// Generate builds the swagger document of the go project in opts.Dir
+// the returned error is the Diagnostics of the run when any of them is an error, warnings alone are not reported.
+func Generate(ctx context.Context, opts Options) (*specs.SwagDoc, error) {
+	swagDoc, diags := Build(ctx, opts)
+	if err := diags.Err(); err != nil {
+		return nil, err
+	}
+	return swagDoc, nil
+}
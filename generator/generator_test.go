@@ -0,0 +1,464 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/sfodje/swagson/specs"
+)
+
+const (
+	APIMETATEXT = `Info:
+    Title: Swagger Petstore
+    Description: |
+        This is a sample server Petstore server.
+        You can find out more about Swagger at [http://swagger.io](http://swagger.io) or on [irc.freenode.net, #swagger](http://swagger.io/irc/).  For this sample, you can use the api key ` + "`special-key`" + ` to test the authorization filters.
+    TermsOfService: "http://swagger.io/terms/"
+    Contact:
+        Name: John Doe
+        Url: "http://swagger.io"
+        Email: apiteam@swagger.io
+    License:
+        Name: Apache 2.0
+        Url: "http://www.apache.org/licenses/LICENSE-2.0.html"
+    Version: 1.0.0
+Host: petstore.swagger.io
+BasePath: /v2
+Consumes:
+    - application/json
+    - application/xml
+Produces:
+    - application/json
+    - application/xml
+Schemes:
+    - http
+SecurityDefinitions:
+    api_key:
+        In: header
+        Name: api_key
+        Type: apiKey
+    petstore_auth:
+        AuthorizationUrl: "http://petstore.swagger.io/api/oauth/dialog"
+        Flow: implicit
+        Type: oauth2
+        Scopes:
+            "read:pets": read your pets
+            "write:pets": modify pets in your account
+ExternalDocs:
+    Description: Find out more about Swagger
+    Url: "http://swagger.io"
+Tags:
+    - Name: pet
+      Description: Everything about your Pets
+      ExternalDocs:
+        Description: Find out more
+        Url: "http://swagger.io"`
+)
+
+func getSwagDoc() *specs.SwagDoc {
+	var swagDoc specs.SwagDoc = specs.SwagDoc{}
+	swagDoc.Swagger = "2.0"
+	swagDoc.Info = &specs.SwagInfo{}
+	swagDoc.Info.Title = "Swagger Petstore"
+	swagDoc.Info.Description = `This is a sample server Petstore server.
+You can find out more about Swagger at [http://swagger.io](http://swagger.io) or on [irc.freenode.net, #swagger](http://swagger.io/irc/).  For this sample, you can use the api key ` + "`special-key`" + ` to test the authorization filters.
+`
+	swagDoc.Info.TermsOfService = "http://swagger.io/terms/"
+	swagDoc.Info.Contact = &specs.SwagContact{}
+	swagDoc.Info.Contact.Name = "John Doe"
+	swagDoc.Info.Contact.Url = "http://swagger.io"
+	swagDoc.Info.Contact.Email = "apiteam@swagger.io"
+	swagDoc.Info.License = &specs.SwagLicense{}
+	swagDoc.Info.License.Name = "Apache 2.0"
+	swagDoc.Info.License.Url = "http://www.apache.org/licenses/LICENSE-2.0.html"
+	swagDoc.Info.Version = "1.0.0"
+	swagDoc.Host = "petstore.swagger.io"
+	swagDoc.BasePath = "/v2"
+	swagDoc.Consumes = &[]string{"application/json", "application/xml"}
+	swagDoc.Produces = &[]string{"application/json", "application/xml"}
+	swagDoc.Schemes = &[]string{"http"}
+	swagDoc.SecurityDefinitions = &map[string]specs.SwagSecDef{}
+	(*swagDoc.SecurityDefinitions)["api_key"] = specs.SwagSecDef{
+		In:   "header",
+		Name: "api_key",
+		Type: "apiKey",
+	}
+	(*swagDoc.SecurityDefinitions)["petstore_auth"] = specs.SwagSecDef{
+		AuthorizationUrl: "http://petstore.swagger.io/api/oauth/dialog",
+		Flow:             "implicit",
+		Type:             "oauth2",
+		Scopes: &map[string]string{
+			"read:pets":  "read your pets",
+			"write:pets": "modify pets in your account",
+		},
+	}
+	swagDoc.ExternalDocs = &specs.SwagExtDoc{
+		Description: "Find out more about Swagger",
+		Url:         "http://swagger.io",
+	}
+	swagDoc.Tags = &[]specs.SwagTag{}
+	swagDoc.Tags = &[]specs.SwagTag{
+		specs.SwagTag{
+			Name:        "pet",
+			Description: "Everything about your Pets",
+			ExternalDocs: &specs.SwagExtDoc{
+				Description: "Find out more",
+				Url:         "http://swagger.io",
+			},
+		},
+	}
+	return &swagDoc
+}
+
+// parseExample parses a file of the examples directory with its comments
+func parseExample(t *testing.T, file string) (*token.FileSet, *ast.File) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fset, f
+}
+
+func Test_loadPackages(t *testing.T) {
+	var diags Diagnostics
+	if pkgs, err := loadPackages(context.Background(), "../examples/", "", nil, &diags); err == nil && len(pkgs) == 1 && len(pkgs[0].Syntax) == 4 && pkgs[0].TypesInfo != nil {
+		t.Log("loadPackages(\"../examples/\", \"\") passed.")
+	} else {
+		t.Log(err)
+		t.Error("loadPackages(\"../examples/\", \"\") failed.")
+	}
+
+	if pkgs, err := loadPackages(context.Background(), "../examples/", "otherpackage", nil, &diags); len(pkgs) == 0 && err != nil {
+		t.Log("loadPackages(\"../examples/\", \"otherpackage\") passed.")
+	} else {
+		t.Log(err)
+		t.Error("loadPackages(\"../examples/\", \"otherpackage\") failed.")
+	}
+
+	if pkgs, err := loadPackages(context.Background(), "./non_existent_folder/", "", nil, &diags); len(pkgs) == 0 && err != nil {
+		t.Log("loadPackages(\"./non_existent_folder/\", \"\") passed.")
+	} else {
+		t.Log(err)
+		t.Error("loadPackages(\"./non_existent_folder/\", \"\") failed.")
+	}
+}
+
+func Test_extractComments(t *testing.T) {
+	if comments := extractComments(parseExample(t, "../examples/api_meta.go")); len(*comments) == 2 && (*comments)[1].Text == "// This is a test" && (*comments)[1].Pos.Line == 48 {
+		t.Log("extractComments(\"../examples/api_meta.go\") passed.")
+	} else {
+		t.Error("extractComments(\"../examples/api_meta.go\") failed.")
+	}
+}
+
+func Test_extractMarkup(t *testing.T) {
+	var markup map[specs.MarkupNode][]markupDoc = make(map[specs.MarkupNode][]markupDoc)
+	comments := extractComments(parseExample(t, "../examples/api_meta.go"))
+	extractMarkup(markup, comments)
+	if APIMETATEXT != markup[specs.APIMETA][0].Text || markup[specs.APIMETA][0].Line != 2 {
+		t.Error("extractMarkup(markup, comments) failed.")
+	} else {
+		t.Log("extractMarkup(markup, comments) passed.")
+	}
+}
+
+func Test_extractMarkup_lineComments(t *testing.T) {
+	var lines = []string{"// api:meta"}
+	for _, line := range strings.Split(APIMETATEXT, "\n") {
+		lines = append(lines, "// "+line)
+	}
+	var markup map[specs.MarkupNode][]markupDoc = make(map[specs.MarkupNode][]markupDoc)
+	extractMarkup(markup, &[]comment{{Text: strings.Join(lines, "\n"), Pos: token.Position{Filename: "meta.go", Line: 1}}})
+	if len(markup[specs.APIMETA]) == 1 && APIMETATEXT == markup[specs.APIMETA][0].Text {
+		t.Log("extractMarkup(markup, line comments) passed.")
+	} else {
+		t.Error("extractMarkup(markup, line comments) failed.")
+	}
+
+	markup = make(map[specs.MarkupNode][]markupDoc)
+	extractMarkup(markup, extractComments(parseExample(t, "../examples/api_route.go")))
+	var swagDoc = new(specs.SwagDoc)
+	err := handleRoute(swagDoc, markup[specs.APIROUTE])
+	if err == nil && len(swagDoc.Paths.Paths) == 2 && swagDoc.Paths.Paths["/store/inventory"].Get.OperationId == "getInventory" && markup[specs.APIROUTE][1].Handler == "getInventory" {
+		t.Log("extractMarkup(markup, gofmt line comments) passed.")
+	} else {
+		t.Log(err)
+		t.Error("extractMarkup(markup, gofmt line comments) failed.")
+	}
+}
+
+func Test_extractSwaggerDoc(t *testing.T) {
+	var markup map[specs.MarkupNode][]markupDoc = make(map[specs.MarkupNode][]markupDoc)
+	comments := extractComments(parseExample(t, "../examples/api_meta.go"))
+	extractMarkup(markup, comments)
+	swagDoc, err := extractSwaggerDoc(&markup)
+	j1, _ := json.Marshal(swagDoc)
+	j2, _ := json.Marshal(getSwagDoc())
+	if err != nil || string(j1) != string(j2) {
+		t.Log(err)
+		t.Error("extractSwaggerDoc(markup) failed.")
+	} else {
+		t.Log("extractSwaggerDoc(markup) passed.")
+	}
+
+}
+
+func Test_handleRoute(t *testing.T) {
+	var swagDoc = new(specs.SwagDoc)
+	docs := []markupDoc{
+		{Node: specs.APIROUTE, File: "get.go", Line: 2, Column: 1, Text: "/pet/{petId}:\n    Get:\n        OperationId: getPetById"},
+		{Node: specs.APIROUTE, File: "post.go", Line: 2, Column: 1, Text: "/pet/{petId}:\n    Post:\n        OperationId: updatePetWithForm"},
+		{Node: specs.APIROUTE, File: "store.go", Line: 2, Column: 1, Text: "/store/inventory:\n    Get:\n        OperationId: getInventory"},
+	}
+	err := handleRoute(swagDoc, docs)
+	if err == nil && len(swagDoc.Paths.Paths) == 2 && swagDoc.Paths.Paths["/pet/{petId}"].Get != nil && swagDoc.Paths.Paths["/pet/{petId}"].Post != nil {
+		t.Log("handleRoute(swagDoc, docs) passed.")
+	} else {
+		t.Log(err)
+		t.Error("handleRoute(swagDoc, docs) failed.")
+	}
+
+	docs = append(docs, markupDoc{Node: specs.APIROUTE, File: "dup.go", Line: 5, Column: 1, Text: "/pet/{petId}:\n    Get:\n        OperationId: findPet"})
+	err = handleRoute(swagDoc, docs)
+	if err != nil && err.Error() == "dup.go:5:1: api:route: GET /pet/{petId} is already declared at get.go:2:1" {
+		t.Log("handleRoute(swagDoc, duplicate docs) passed.")
+	} else {
+		t.Log(err)
+		t.Error("handleRoute(swagDoc, duplicate docs) failed.")
+	}
+
+	param := "    Parameters:\n        - Name: petId\n          In: path\n          Required: true\n          Type: %s\n"
+	docs = []markupDoc{
+		{Node: specs.APIROUTE, File: "get.go", Line: 2, Column: 1, Text: "/pet/{petId}:\n" + fmt.Sprintf(param, "integer") + "    Get:\n        OperationId: getPetById"},
+		{Node: specs.APIROUTE, File: "post.go", Line: 2, Column: 1, Text: "/pet/{petId}:\n" + fmt.Sprintf(param, "integer") + "    Post:\n        OperationId: updatePetWithForm"},
+	}
+	err = handleRoute(swagDoc, docs)
+	if err == nil && len(*swagDoc.Paths.Paths["/pet/{petId}"].Parameters) == 1 {
+		t.Log("handleRoute(swagDoc, repeated path parameters) passed.")
+	} else {
+		t.Log(err)
+		t.Error("handleRoute(swagDoc, repeated path parameters) failed.")
+	}
+
+	docs[1].Text = "/pet/{petId}:\n" + fmt.Sprintf(param, "string") + "    Post:\n        OperationId: updatePetWithForm"
+	err = handleRoute(swagDoc, docs)
+	if err != nil && err.Error() == "post.go:2:1: api:route: parameter path petId of /pet/{petId} is declared differently at get.go:2:1" {
+		t.Log("handleRoute(swagDoc, conflicting path parameters) passed.")
+	} else {
+		t.Log(err)
+		t.Error("handleRoute(swagDoc, conflicting path parameters) failed.")
+	}
+}
+
+func Test_handleRoute_extensions(t *testing.T) {
+	var swagDoc = new(specs.SwagDoc)
+	docs := []markupDoc{
+		{Node: specs.APIROUTE, File: "get.go", Line: 2, Column: 1, Text: "x-paths: get\n/pet:\n    Get:\n        Responses:\n            200:\n                Description: ok\n            x-rate-limit: 10"},
+		{Node: specs.APIROUTE, File: "post.go", Line: 2, Column: 1, Text: "/pet:\n    Post:\n        Responses:\n            200:\n                Description: ok"},
+	}
+	var keys error
+	for _, doc := range docs {
+		if err := checkKeys(doc); err != nil {
+			keys = err
+		}
+	}
+	err := handleRoute(swagDoc, docs)
+	_, paths := swagDoc.Paths.Extensions.Get("x-paths")
+	limit, _ := swagDoc.Paths.Paths["/pet"].Get.Responses.Extensions.Get("x-rate-limit")
+	if err == nil && keys == nil && paths && string(limit) == "10" && len(swagDoc.Paths.Paths["/pet"].Get.Responses.Responses) == 1 {
+		t.Log("handleRoute(swagDoc, extensions) passed.")
+	} else {
+		t.Log(err, keys)
+		t.Error("handleRoute(swagDoc, extensions) failed.")
+	}
+
+	docs = append(docs, markupDoc{Node: specs.APIROUTE, File: "dup.go", Line: 5, Column: 1, Text: "x-paths: dup\n/store:\n    Get:\n        OperationId: getStore"})
+	err = handleRoute(swagDoc, docs)
+	if err != nil && err.Error() == "dup.go:5:1: api:route: x-paths is already declared at get.go:2:1" {
+		t.Log("handleRoute(swagDoc, duplicate extensions) passed.")
+	} else {
+		t.Log(err)
+		t.Error("handleRoute(swagDoc, duplicate extensions) failed.")
+	}
+}
+
+func Test_handleModel(t *testing.T) {
+	var swagDoc = new(specs.SwagDoc)
+	docs := []markupDoc{
+		{Node: specs.APIMODEL, File: "pet.go", Line: 2, Column: 1, Text: "Pet:\n    Type: object"},
+		{Node: specs.APIMODEL, File: "dup.go", Line: 8, Column: 1, Text: "Tag:\n    Type: object\nPet:\n    Type: string"},
+	}
+	err := handleModel(swagDoc, docs)
+	if err != nil && err.Error() == "dup.go:8:1: api:model: definition Pet is already declared at pet.go:2:1" && len(*swagDoc.Definitions) == 2 {
+		t.Log("handleModel(swagDoc, duplicate docs) passed.")
+	} else {
+		t.Log(err)
+		t.Error("handleModel(swagDoc, duplicate docs) failed.")
+	}
+}
+
+func Test_mergeNamed(t *testing.T) {
+	var swagDoc = &specs.SwagDoc{Parameters: &map[string]specs.SwagParam{"limit": {Name: "limit", In: "query"}}}
+	docs := []markupDoc{
+		{Node: specs.APIPARAMETER, File: "page.go", Line: 2, Column: 1, Text: "offset:\n    Name: offset\n    In: query\n    Type: integer"},
+		{Node: specs.APIPARAMETER, File: "list.go", Line: 4, Column: 1, Text: "limit:\n    Name: limit\n    In: query\n    Type: integer"},
+	}
+	err := mergeNamed(&swagDoc.Parameters, docs, "parameter")
+	if err != nil && err.Error() == "list.go:4:1: api:parameter: parameter limit is already declared in api:meta" && len(*swagDoc.Parameters) == 2 && (*swagDoc.Parameters)["offset"].Type == "integer" {
+		t.Log("mergeNamed(parameters declared in api:meta) passed.")
+	} else {
+		t.Log(err)
+		t.Error("mergeNamed(parameters declared in api:meta) failed.")
+	}
+
+	var markup = map[specs.MarkupNode][]markupDoc{
+		specs.APIRESPONSE:           {{Node: specs.APIRESPONSE, File: "errors.go", Line: 2, Column: 1, Text: "NotFound:\n    Description: Entity not found"}},
+		specs.APISECURITYDEFINITION: {{Node: specs.APISECURITYDEFINITION, File: "auth.go", Line: 2, Column: 1, Text: "basic_auth:\n    Type: basic"}},
+	}
+	swagDoc, err = extractSwaggerDoc(&markup)
+	if err == nil && (*swagDoc.Responses)["NotFound"].Description == "Entity not found" && (*swagDoc.SecurityDefinitions)["basic_auth"].Type == "basic" {
+		t.Log("extractSwaggerDoc(api:response, api:securityDefinition) passed.")
+	} else {
+		t.Log(err)
+		t.Error("extractSwaggerDoc(api:response, api:securityDefinition) failed.")
+	}
+}
+
+func Test_Generate(t *testing.T) {
+	swagDoc, err := Generate(context.Background(), Options{Dir: "../examples/"})
+	if err == nil && swagDoc.Info != nil && len(swagDoc.Paths.Paths) > 0 && (*swagDoc.Definitions)["Order"].Type == "object" {
+		t.Log("Generate(examples) passed.")
+	} else {
+		t.Log(err)
+		t.Error("Generate(examples) failed.")
+	}
+
+	swagDoc, err = Generate(context.Background(), Options{Dir: "../examples/", Exclude: []string{"api_meta.go"}})
+	if diags, ok := err.(Diagnostics); ok && swagDoc == nil && diags.HasErrors() && strings.Contains(err.Error(), "Missing required property: 'info'") {
+		t.Log("Generate(examples without api:meta) passed.")
+	} else {
+		t.Log(err)
+		t.Error("Generate(examples without api:meta) failed.")
+	}
+}
+
+func Test_Generate_deterministic(t *testing.T) {
+	var outputs [2][3][]byte
+	for i, workers := range []int{1, 8} {
+		swagDoc, err := Generate(context.Background(), Options{Dir: "../examples/", Workers: workers})
+		if err != nil {
+			t.Fatal(err)
+		}
+		j, err := DocToJson(swagDoc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		y, err := DocToYaml(swagDoc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		doc, err := SwagDocToOpenAPI3(swagDoc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		o, err := DocToJson(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		outputs[i] = [3][]byte{*j, *y, *o}
+	}
+	for i, format := range []string{"json", "yaml", "openapi"} {
+		if string(outputs[0][i]) == string(outputs[1][i]) {
+			t.Logf("Generate(examples, twice, %s) passed.", format)
+		} else {
+			t.Errorf("Generate(examples, twice, %s) failed.", format)
+		}
+	}
+
+	decoded, err := decodeOrdered(outputs[0][0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := decoded.(*ordered)
+	paths := doc.values["paths"].(*ordered)
+	expected := []string{"swagger", "info", "host", "basePath", "schemes", "consumes", "produces", "paths", "definitions", "parameters", "responses", "securityDefinitions", "tags", "externalDocs"}
+	if strings.Join(doc.keys, ",") == strings.Join(expected, ",") && sort.StringsAreSorted(paths.keys) {
+		t.Log("DocToJson(examples, key order) passed.")
+	} else {
+		t.Log(doc.keys, paths.keys)
+		t.Error("DocToJson(examples, key order) failed.")
+	}
+}
+
+func Test_handleModel_composition(t *testing.T) {
+	var swagDoc = new(specs.SwagDoc)
+	docs := []markupDoc{{Node: specs.APIMODEL, File: "pet.go", Line: 2, Column: 1, Text: `Pet:
+    Type: object
+    Discriminator: petType
+    Required:
+        - petType
+    Properties:
+        id:
+            Type: integer
+            ReadOnly: true
+        petType:
+            Type: string
+            Enum: [cat, dog]
+        attributes:
+            Type: object
+            AdditionalProperties:
+                Type: integer
+                Minimum: 0.5
+    ExternalDocs:
+        Url: "http://swagger.io"
+Cat:
+    AllOf:
+        - $ref: "#/definitions/Pet"
+        - Type: object
+          AdditionalProperties: false
+          Properties:
+              huntingSkill:
+                  Type: string
+                  Default: lazy
+                  Example: adventurous`}}
+	if err := handleModel(swagDoc, docs); err != nil {
+		t.Fatal(err)
+	}
+
+	j, err := DocToJson(swagDoc.Definitions)
+	expected := `{"Cat":{"allOf":[{"$ref":"#/definitions/Pet"},{"type":"object","properties":{"huntingSkill":{"default":"lazy","type":"string","example":"adventurous"}},"additionalProperties":false}]},` +
+		`"Pet":{"required":["petType"],"type":"object","properties":{"attributes":{"type":"object","additionalProperties":{"minimum":0.5,"type":"integer"}},` +
+		`"id":{"type":"integer","readOnly":true},"petType":{"enum":["cat","dog"],"type":"string"}},"discriminator":"petType","externalDocs":{"url":"http://swagger.io"}}}`
+	if err == nil && string(*j) == expected {
+		t.Log("handleModel(swagDoc, allOf, additionalProperties, discriminator) passed.")
+	} else {
+		t.Log(err, string(*j))
+		t.Error("handleModel(swagDoc, allOf, additionalProperties, discriminator) failed.")
+	}
+
+	y, err := DocToYaml(swagDoc.Definitions)
+	if err == nil && strings.Contains(string(*y), "additionalProperties: false") && strings.Contains(string(*y), "discriminator: petType") {
+		t.Log("DocToYaml(composed schemas) passed.")
+	} else {
+		t.Log(err)
+		t.Error("DocToYaml(composed schemas) failed.")
+	}
+
+	pet := (*swagDoc.Definitions)["Pet"]
+	rewriteSchemaRefs(&pet)
+	j, err = DocToJson(pet.Discriminator)
+	if err == nil && string(*j) == `{"propertyName":"petType"}` && !(*swagDoc.Definitions)["Pet"].Discriminator.Object {
+		t.Log("rewriteSchemaRefs(discriminator) passed.")
+	} else {
+		t.Log(err, string(*j))
+		t.Error("rewriteSchemaRefs(discriminator) failed.")
+	}
+}
@@ -0,0 +1,105 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"go/token"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// LOADMODE is the information go/packages loads for every package in the project
+const LOADMODE = packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+	packages.NeedTypes | packages.NeedTypesInfo
+
+// loadPackages loads every package under the given directory through go/packages
+// packages are loaded module-aware with syntax and type information, honouring build constraints.
+// When pkg is not empty only packages with that name are returned, tags are the extra build tags to honour.
+// Package errors are added to diags: type errors are warnings since markup extraction only needs
+// the syntax tree, list and parse errors are errors. The returned error is set when nothing could be loaded.
+// Packages are returned sorted by import path so the markup is read in the same order on every run.
+func loadPackages(ctx context.Context, dir string, pkg string, tags []string, diags *Diagnostics) ([]*packages.Package, error) {
+	return loadPatterns(ctx, dir, pkg, tags, []string{"./..."}, diags)
+}
+
+// loadPatterns loads the packages matching the given patterns like loadPackages loads the packages under dir
+func loadPatterns(ctx context.Context, dir string, pkg string, tags []string, patterns []string, diags *Diagnostics) ([]*packages.Package, error) {
+	pkgs, err := packages.Load(loadConfig(ctx, dir, tags, LOADMODE), patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	var loaded []*packages.Package
+	for _, p := range pkgs {
+		if len(pkg) > 0 && strings.ToLower(pkg) != strings.ToLower(p.Name) {
+			continue
+		}
+		for _, e := range p.Errors {
+			if e.Kind == packages.TypeError {
+				diags.warning(e)
+			} else {
+				diags.error(e)
+			}
+		}
+		if len(p.Syntax) > 0 {
+			loaded = append(loaded, p)
+		}
+	}
+	if len(loaded) == 0 {
+		return nil, fmt.Errorf("no Go packages found in %s", dir)
+	}
+	sort.SliceStable(loaded, func(i, j int) bool {
+		return loaded[i].PkgPath < loaded[j].PkgPath
+	})
+	return loaded, nil
+}
+
+// LISTMODE is the information listPackages gets for every package and its dependencies, it takes no parsing or type checking
+const LISTMODE = packages.NeedName | packages.NeedFiles | packages.NeedModule | packages.NeedImports | packages.NeedDeps
+
+// listPackages lists the packages under the given directory the way loadPackages loads them, without reading the files
+// the go files of every package are sorted by name and its imports are listed along with their own imports.
+// Any package error fails the listing, loadPackages reports them.
+func listPackages(ctx context.Context, dir string, pkg string, tags []string) ([]*packages.Package, error) {
+	pkgs, err := packages.Load(loadConfig(ctx, dir, tags, LISTMODE), "./...")
+	if err != nil {
+		return nil, err
+	}
+
+	var listed []*packages.Package
+	for _, p := range pkgs {
+		if len(pkg) > 0 && strings.ToLower(pkg) != strings.ToLower(p.Name) {
+			continue
+		}
+		if len(p.Errors) > 0 {
+			return nil, p.Errors[0]
+		}
+		if len(p.GoFiles) > 0 {
+			sort.Strings(p.GoFiles)
+			listed = append(listed, p)
+		}
+	}
+	if len(listed) == 0 {
+		return nil, fmt.Errorf("no Go packages found in %s", dir)
+	}
+	sort.SliceStable(listed, func(i, j int) bool {
+		return listed[i].PkgPath < listed[j].PkgPath
+	})
+	return listed, nil
+}
+
+// loadConfig returns the go/packages configuration shared by loadPackages and listPackages
+func loadConfig(ctx context.Context, dir string, tags []string, mode packages.LoadMode) *packages.Config {
+	cfg := &packages.Config{
+		Context: ctx,
+		Mode:    mode,
+		Dir:     dir,
+		Fset:    token.NewFileSet(),
+	}
+	if len(tags) > 0 {
+		cfg.BuildFlags = []string{"-tags=" + strings.Join(tags, ",")}
+	}
+	return cfg
+}
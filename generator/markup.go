@@ -0,0 +1,123 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sfodje/swagson/specs"
+)
+
+// yamlLine matches the line number reported in yaml syntax errors
+var yamlLine = regexp.MustCompile(`^(?:error converting YAML to JSON: )?yaml: line (\d+): (.*)$`)
+
+// markupDoc is the body of a single markup comment along with its markup node and position
+// Line is the line the body starts at, Column the column of the comment itself.
+// Handler is the name of the function the comment documents, if any, and Routes the registrations of that handler
+// when the comment only holds the operation
+type markupDoc struct {
+	Node    specs.MarkupNode
+	File    string
+	Line    int
+	Column  int
+	Text    string
+	Handler string
+	Routes  []route
+}
+
+// position returns the position of the given line of the markup body, counting from zero
+func (m markupDoc) position(offset int) token.Position {
+	return token.Position{Filename: m.File, Line: m.Line + offset, Column: m.Column}
+}
+
+// errorf returns a markupError positioned at the start of the markup body
+func (m markupDoc) errorf(format string, args ...interface{}) error {
+	return m.errorAt(0, format, args...)
+}
+
+// errorAt returns a markupError positioned at the given line of the markup body, counting from zero
+func (m markupDoc) errorAt(offset int, format string, args ...interface{}) error {
+	return &markupError{Pos: m.position(offset), Node: m.Node, Msg: fmt.Sprintf(format, args...)}
+}
+
+// wrap positions an error raised while decoding the markup body
+// yaml syntax errors carry a line number relative to the body which is translated into a source line
+func (m markupDoc) wrap(err error) error {
+	var duplicate *duplicateKeyError
+	if errors.As(err, &duplicate) {
+		return m.errorAt(duplicate.Line-1, "key %s is already set at line %d", duplicate.Key, m.position(duplicate.Previous-1).Line)
+	}
+	if match := yamlLine.FindStringSubmatch(err.Error()); match != nil {
+		line, _ := strconv.Atoi(match[1])
+		return &markupError{Pos: m.position(line - 1), Node: m.Node, Msg: "yaml: " + match[2]}
+	}
+	return &markupError{Pos: m.position(0), Node: m.Node, Msg: err.Error()}
+}
+
+// decode converts the yaml markup body to json and unmarshals it into v
+// for some reason yaml.Unmarshal throws error: "panic: reflect: reflect.Value.Set using unaddressable value"
+// with structs that have nested pointers
+// as a workaround, convert yaml string to json string and unmarshal
+// keys are matched to the fields of v following the key policy, see checkKeys for the keys left out
+func (m markupDoc) decode(v interface{}) error {
+	body, _, err := m.canonical(reflect.TypeOf(v))
+	if err != nil {
+		return err
+	}
+	j, err := json.Marshal(body)
+	if err != nil {
+		return m.wrap(err)
+	}
+	err = json.Unmarshal(j, v)
+	if err != nil {
+		return m.wrap(err)
+	}
+	return nil
+}
+
+// json converts the yaml markup body to json, keeping the order of its keys
+func (m markupDoc) json() ([]byte, error) {
+	j, err := yamlToJSON([]byte(m.Text))
+	if err != nil {
+		return nil, m.wrap(err)
+	}
+	return j, nil
+}
+
+// markupError is an error tied to a markup comment
+// it prints as file.go:12:3: api:route: message so editors and CI annotations can jump to the comment
+type markupError struct {
+	Pos  token.Position
+	Node specs.MarkupNode
+	Msg  string
+}
+
+func (e *markupError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", displayPosition(e.Pos), e.Node, e.Msg)
+}
+
+// displayPosition formats a position as file:line:column with the file relative to the working directory
+func displayPosition(pos token.Position) string {
+	pos.Filename = displayPath(pos.Filename)
+	return pos.String()
+}
+
+// displayPath returns the file relative to the working directory when it lies below it
+func displayPath(file string) string {
+	wd, err := os.Getwd()
+	if err != nil || !filepath.IsAbs(file) {
+		return file
+	}
+	rel, err := filepath.Rel(wd, file)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return file
+	}
+	return rel
+}
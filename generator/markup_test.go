@@ -0,0 +1,26 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/sfodje/swagson/specs"
+)
+
+func Test_markupDoc_decode(t *testing.T) {
+	doc := markupDoc{Node: specs.APIROUTE, File: "pet.go", Line: 12, Column: 3, Text: "/pet:\n    Get:\n      Summary: a\n     Tags: b"}
+	var paths map[string]specs.SwagPath
+	if err := doc.decode(&paths); err != nil && err.Error() == "pet.go:12:3: api:route: yaml: did not find expected key" {
+		t.Log("markupDoc.decode(yaml syntax error) passed.")
+	} else {
+		t.Log(err)
+		t.Error("markupDoc.decode(yaml syntax error) failed.")
+	}
+
+	doc.Text = "/pet:\n    Get: find pets"
+	if err := doc.decode(&paths); err != nil && err.(*markupError).Pos.Line == 12 {
+		t.Log("markupDoc.decode(type error) passed.")
+	} else {
+		t.Log(err)
+		t.Error("markupDoc.decode(type error) failed.")
+	}
+}
@@ -0,0 +1,160 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/sfodje/swagson/specs"
+)
+
+// handleMeta extracts data from api:meta markup
+// the fragments of every api:meta comment are merged into one document so Info, SecurityDefinitions and Tags
+// can live next to the code they describe. Objects are merged key by key, lists are concatenated and
+// list entries sharing a name, like tags, are merged into one. A scalar set to different values is an error
+// naming both source locations. Fragments are merged in file order so the result does not depend on load order.
+func handleMeta(swagDoc *specs.SwagDoc, docs []markupDoc) error {
+	swagDoc.Swagger = "2.0"
+	docs = append([]markupDoc{}, docs...)
+	sort.SliceStable(docs, func(i, j int) bool {
+		if docs[i].File != docs[j].File {
+			return docs[i].File < docs[j].File
+		}
+		return docs[i].Line < docs[j].Line
+	})
+
+	var merged = newOrdered()
+	var sources = make(map[string]markupDoc)
+	var errs errorList
+	for _, doc := range docs {
+		// decoding into the document first reports type errors at the fragment they come from
+		if err := doc.decode(new(specs.SwagDoc)); err != nil {
+			errs.add(err)
+			continue
+		}
+		// fragments are merged with their keys spelled like the spec, unknown keys were reported by checkKeys
+		fragment, _, err := doc.canonical(reflect.TypeOf(swagDoc))
+		if err != nil {
+			errs.add(err)
+			continue
+		}
+		if o, ok := fragment.(*ordered); ok {
+			errs.add(mergeMeta(merged, o, "", doc, sources))
+		}
+	}
+
+	j, err := json.Marshal(merged)
+	if err != nil {
+		errs.add(err)
+		return errs.err()
+	}
+	errs.add(json.Unmarshal(j, swagDoc))
+	return errs.err()
+}
+
+// mergeMeta merges the decoded fragment src into dst, path is the dotted path of dst within the document
+// sources records the fragment every scalar was first set by so conflicts can name both locations
+func mergeMeta(dst *ordered, src *ordered, path string, doc markupDoc, sources map[string]markupDoc) error {
+	var errs errorList
+	for _, key := range src.keys {
+		child := strings.TrimPrefix(path+"."+key, ".")
+		value, merged, err := mergeMetaValue(dst.values[key], src.values[key], child, doc, sources)
+		errs.add(err)
+		if merged {
+			dst.set(key, value)
+		}
+	}
+	return errs.err()
+}
+
+// mergeMetaValue merges src into the existing value dst and reports whether the result should be stored
+func mergeMetaValue(dst interface{}, src interface{}, path string, doc markupDoc, sources map[string]markupDoc) (interface{}, bool, error) {
+	if dst == nil {
+		sources[path] = doc
+		return src, true, nil
+	}
+	switch s := src.(type) {
+	case *ordered:
+		if d, ok := dst.(*ordered); ok {
+			return d, true, mergeMeta(d, s, path, doc, sources)
+		}
+	case []interface{}:
+		if d, ok := dst.([]interface{}); ok {
+			return mergeMetaList(d, s, path, doc, sources)
+		}
+	default:
+		if reflect.DeepEqual(dst, src) {
+			return dst, false, nil
+		}
+	}
+	prev := displayPosition(metaSource(sources, path).position(0))
+	return dst, false, doc.errorf("%s is set to %s here and to %s at %s", path, metaValue(src), metaValue(dst), prev)
+}
+
+// mergeMetaList concatenates two lists, skipping entries already present
+// objects with a name, like tags, are merged with the entry of the same name
+func mergeMetaList(dst []interface{}, src []interface{}, path string, doc markupDoc, sources map[string]markupDoc) (interface{}, bool, error) {
+	var errs errorList
+	for _, item := range src {
+		var found = false
+		for i, existing := range dst {
+			if reflect.DeepEqual(existing, item) {
+				found = true
+				break
+			}
+			name := metaName(item)
+			if name != "" && name == metaName(existing) {
+				value, _, err := mergeMetaValue(existing, item, fmt.Sprintf("%s[%s]", path, name), doc, sources)
+				errs.add(err)
+				dst[i] = value
+				found = true
+				break
+			}
+		}
+		if !found {
+			if name := metaName(item); name != "" {
+				sources[fmt.Sprintf("%s[%s]", path, name)] = doc
+			}
+			dst = append(dst, item)
+		}
+	}
+	return dst, true, errs.err()
+}
+
+// metaSource returns the fragment the value at path was set by
+// values copied along with their parent object are recorded under the path of that parent
+func metaSource(sources map[string]markupDoc, path string) markupDoc {
+	for path != "" {
+		if doc, ok := sources[path]; ok {
+			return doc
+		}
+		path = path[:strings.LastIndexAny(path, ".[")+1]
+		path = strings.TrimRight(path, ".[")
+	}
+	return markupDoc{}
+}
+
+// metaName returns the name of a list entry, or an empty string when it is not a named object
+func metaName(v interface{}) string {
+	o, ok := v.(*ordered)
+	if !ok {
+		return ""
+	}
+	for k, name := range o.values {
+		if s, ok := name.(string); ok && k == "name" {
+			return s
+		}
+	}
+	return ""
+}
+
+// metaValue formats a decoded value for an error message
+func metaValue(v interface{}) string {
+	j, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(j)
+}
@@ -0,0 +1,34 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/sfodje/swagson/specs"
+)
+
+func Test_handleMeta(t *testing.T) {
+	var swagDoc = new(specs.SwagDoc)
+	docs := []markupDoc{
+		{Node: specs.APIMETA, File: "b/auth.go", Line: 2, Column: 1, Text: "SecurityDefinitions:\n    api_key:\n        Type: apiKey\n        Name: api_key\n        In: header\nTags:\n    - Name: store\n    - Name: pet\n      Description: Everything about your Pets"},
+		{Node: specs.APIMETA, File: "a/info.go", Line: 2, Column: 1, Text: "Info:\n    Title: Swagger Petstore\n    Version: 1.0.0\nSchemes:\n    - https\nTags:\n    - Name: pet"},
+	}
+	err := handleMeta(swagDoc, docs)
+	if err == nil && swagDoc.Info.Title == "Swagger Petstore" && len(*swagDoc.SecurityDefinitions) == 1 &&
+		len(*swagDoc.Tags) == 2 && (*swagDoc.Tags)[0].Name == "pet" && (*swagDoc.Tags)[0].Description == "Everything about your Pets" {
+		t.Log("handleMeta(swagDoc, fragments) passed.")
+	} else {
+		t.Log(err, swagDoc.Tags)
+		t.Error("handleMeta(swagDoc, fragments) failed.")
+	}
+
+	docs = append(docs, markupDoc{Node: specs.APIMETA, File: "c/version.go", Line: 7, Column: 1, Text: "info:\n    version: 2.0.0\nTags:\n    - Name: pet\n      Description: Pets"})
+	err = handleMeta(new(specs.SwagDoc), docs)
+	expected := "c/version.go:7:1: api:meta: info.version is set to \"2.0.0\" here and to \"1.0.0\" at a/info.go:2:1\n" +
+		"c/version.go:7:1: api:meta: tags[pet].description is set to \"Pets\" here and to \"Everything about your Pets\" at b/auth.go:2:1"
+	if err != nil && err.Error() == expected {
+		t.Log("handleMeta(swagDoc, conflicting fragments) passed.")
+	} else {
+		t.Log(err)
+		t.Error("handleMeta(swagDoc, conflicting fragments) failed.")
+	}
+}
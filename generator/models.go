@@ -0,0 +1,319 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"github.com/sfodje/swagson/specs"
+	"golang.org/x/tools/go/packages"
+)
+
+// DEFINITIONREF is the prefix of every reference to a generated definition
+const DEFINITIONREF = "#/definitions/"
+
+// typeRegistry holds the struct types marked with an api:model comment
+// along with the doc comments of those types and of every struct field declared in the project.
+// Schemas are built from type information, so referenced types are followed across packages and modules.
+type typeRegistry struct {
+	fset   *token.FileSet
+	models []*types.TypeName
+	docs   map[types.Object]string
+}
+
+func newTypeRegistry() *typeRegistry {
+	return &typeRegistry{docs: make(map[types.Object]string)}
+}
+
+// addPackage registers the type declarations of the given files of a loaded package
+// a struct type is marked as a model when the first line of its doc comment contains api:model,
+// the remaining lines of the doc comment become the description of the generated schema
+func (r *typeRegistry) addPackage(pkg *packages.Package, files []*ast.File) {
+	r.fset = pkg.Fset
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				r.addFieldDocs(pkg.TypesInfo, st)
+
+				obj, ok := pkg.TypesInfo.Defs[ts.Name].(*types.TypeName)
+				doc := modelDoc(gen, ts)
+				if !ok || doc == nil {
+					continue
+				}
+				lines := strings.Split(strings.TrimSpace(doc.Text()), "\n")
+				r.models = append(r.models, obj)
+				r.docs[obj] = strings.TrimSpace(strings.Join(lines[1:], "\n"))
+			}
+		}
+	}
+}
+
+// modelDoc returns the doc comment of a type declaration if it marks a struct type with api:model
+func modelDoc(gen *ast.GenDecl, ts *ast.TypeSpec) *ast.CommentGroup {
+	if _, ok := ts.Type.(*ast.StructType); !ok {
+		return nil
+	}
+	doc := ts.Doc
+	if doc == nil && len(gen.Specs) == 1 {
+		doc = gen.Doc
+	}
+	if doc == nil {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSpace(doc.Text()), "\n")
+	if !strings.Contains(strings.ToLower(lines[0]), specs.APIMODEL.String()) {
+		return nil
+	}
+	return doc
+}
+
+// modelDocs returns the doc comments of every struct type in the file marked with api:model
+func modelDocs(f *ast.File) map[*ast.CommentGroup]bool {
+	var docs = make(map[*ast.CommentGroup]bool)
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			if doc := modelDoc(gen, spec.(*ast.TypeSpec)); doc != nil {
+				docs[doc] = true
+			}
+		}
+	}
+	return docs
+}
+
+// addFieldDocs records the doc comments of the fields of a struct, including nested anonymous structs
+func (r *typeRegistry) addFieldDocs(info *types.Info, st *ast.StructType) {
+	ast.Inspect(st, func(n ast.Node) bool {
+		field, ok := n.(*ast.Field)
+		if !ok {
+			return true
+		}
+		for _, ident := range field.Names {
+			if obj := info.Defs[ident]; obj != nil {
+				r.docs[obj] = fieldDoc(field)
+			}
+		}
+		return true
+	})
+}
+
+// schemas builds a definition for every given struct type and for every named struct type they reference
+// definitions are named after their type, two types of the same name in different packages are an error
+// naming both declarations since their references could not be told apart. The types every definition
+// was generated from are returned along with the definitions.
+func (r *typeRegistry) schemas(models []*types.TypeName) (map[string]specs.SwagSchema, map[string]*types.TypeName, error) {
+	var defs = make(map[string]specs.SwagSchema)
+	var objects = make(map[string]*types.TypeName)
+	var pending = append([]*types.TypeName{}, models...)
+	var reported = make(map[*types.TypeName]bool)
+	var errs errorList
+	for len(pending) > 0 {
+		obj := pending[0]
+		pending = pending[1:]
+		if prev, ok := objects[obj.Name()]; ok {
+			if prev != obj && !reported[obj] {
+				reported[obj] = true
+				errs.add(definitionCollision(obj.Name(), qualifiedName(obj), r.position(obj), qualifiedName(prev), r.position(prev)))
+			}
+			continue
+		}
+		var refs []*types.TypeName
+		schema := r.schemaFor(obj.Type().Underlying(), &refs)
+		schema.Description = r.docs[obj]
+		defs[obj.Name()] = schema
+		objects[obj.Name()] = obj
+		pending = append(pending, refs...)
+	}
+	return defs, objects, errs.err()
+}
+
+// marked returns the struct types of pkg marked with an api:model comment
+func (r *typeRegistry) marked(pkg *types.Package) []*types.TypeName {
+	var models []*types.TypeName
+	for _, obj := range r.models {
+		if obj.Pkg() == pkg {
+			models = append(models, obj)
+		}
+	}
+	return models
+}
+
+// definitionCollision is the error of a struct type generating a definition already generated from another type
+func definitionCollision(name string, typ string, pos token.Position, prev string, prevPos token.Position) error {
+	return &markupError{Pos: pos, Node: specs.APIMODEL,
+		Msg: fmt.Sprintf("definition %s of %s is already generated from %s at %s", name, typ, prev, displayPosition(prevPos))}
+}
+
+// position returns the position of a type declaration
+func (r *typeRegistry) position(obj *types.TypeName) token.Position {
+	if r.fset == nil {
+		return token.Position{}
+	}
+	return r.fset.Position(obj.Pos())
+}
+
+// qualifiedName returns the name of a type along with the path of its package
+func qualifiedName(obj *types.TypeName) string {
+	if obj.Pkg() == nil {
+		return obj.Name()
+	}
+	return obj.Pkg().Path() + "." + obj.Name()
+}
+
+// schemaFor converts a go type into a schema
+// named struct types are referenced through $ref and appended to refs
+func (r *typeRegistry) schemaFor(t types.Type, refs *[]*types.TypeName) specs.SwagSchema {
+	switch t := types.Unalias(t).(type) {
+	case *types.Basic:
+		return basicSchema(t)
+	case *types.Named:
+		obj := t.Obj()
+		if obj.Pkg() != nil {
+			switch obj.Pkg().Path() + "." + obj.Name() {
+			case "time.Time":
+				return specs.SwagSchema{Type: "string", Format: "date-time"}
+			case "encoding/json.RawMessage":
+				return specs.SwagSchema{}
+			}
+		}
+		if _, ok := t.Underlying().(*types.Struct); ok {
+			*refs = append(*refs, obj)
+			return specs.SwagSchema{Ref: DEFINITIONREF + obj.Name()}
+		}
+		return r.schemaFor(t.Underlying(), refs)
+	case *types.Pointer:
+		return r.schemaFor(t.Elem(), refs)
+	case *types.Slice:
+		return r.arraySchema(t.Elem(), refs)
+	case *types.Array:
+		return r.arraySchema(t.Elem(), refs)
+	case *types.Map:
+		values := r.schemaFor(t.Elem(), refs)
+		return specs.SwagSchema{Type: "object", AdditionalProperties: &specs.SwagSchemaOrBool{Allows: true, Schema: &values}}
+	case *types.Struct:
+		return r.structSchema(t, refs)
+	}
+	// interfaces, channels and funcs accept any value
+	return specs.SwagSchema{}
+}
+
+// arraySchema returns the schema of a slice or array, byte slices are base64 strings like in encoding/json
+func (r *typeRegistry) arraySchema(elem types.Type, refs *[]*types.TypeName) specs.SwagSchema {
+	if b, ok := elem.(*types.Basic); ok && b.Kind() == types.Byte {
+		return specs.SwagSchema{Type: "string", Format: "byte"}
+	}
+	items := r.schemaFor(elem, refs)
+	return specs.SwagSchema{Type: "array", Items: &items}
+}
+
+// structSchema builds an object schema from the exported fields of a struct
+// property names follow encoding/json, fields without omitempty that are not pointers are required
+func (r *typeRegistry) structSchema(st *types.Struct, refs *[]*types.TypeName) specs.SwagSchema {
+	var schema = specs.SwagSchema{Type: "object"}
+	var properties = make(map[string]specs.SwagSchema)
+	var required []string
+	r.addFields(st, properties, &required, refs)
+	if len(properties) > 0 {
+		schema.Properties = &properties
+	}
+	if len(required) > 0 {
+		schema.Required = &required
+	}
+	return schema
+}
+
+// addFields adds the properties of every field of the struct, flattening embedded structs like encoding/json does
+func (r *typeRegistry) addFields(st *types.Struct, properties map[string]specs.SwagSchema, required *[]string, refs *[]*types.TypeName) {
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		opts := strings.Split(reflect.StructTag(st.Tag(i)).Get("json"), ",")
+		if opts[0] == "-" && len(opts) == 1 {
+			continue
+		}
+
+		if field.Embedded() && opts[0] == "" {
+			t := field.Type()
+			if p, ok := t.(*types.Pointer); ok {
+				t = p.Elem()
+			}
+			if embedded, ok := t.Underlying().(*types.Struct); ok {
+				r.addFields(embedded, properties, required, refs)
+				continue
+			}
+		}
+		if !field.Exported() {
+			continue
+		}
+
+		name := field.Name()
+		if opts[0] != "" {
+			name = opts[0]
+		}
+		schema := r.schemaFor(field.Type(), refs)
+		if hasOption(opts, "string") && schema.Ref == "" {
+			schema.Type = "string"
+		}
+		if schema.Ref == "" {
+			schema.Description = r.docs[field]
+		}
+		properties[name] = schema
+
+		if _, ptr := field.Type().(*types.Pointer); !ptr && !hasOption(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// fieldDoc returns the doc comment of a field, falling back to its trailing line comment
+func fieldDoc(field *ast.Field) string {
+	if field.Doc != nil {
+		return strings.TrimSpace(field.Doc.Text())
+	}
+	if field.Comment != nil {
+		return strings.TrimSpace(field.Comment.Text())
+	}
+	return ""
+}
+
+func hasOption(opts []string, option string) bool {
+	for _, opt := range opts[1:] {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// basicSchema returns the schema of a predeclared go type
+func basicSchema(t *types.Basic) specs.SwagSchema {
+	switch t.Kind() {
+	case types.String:
+		return specs.SwagSchema{Type: "string"}
+	case types.Bool:
+		return specs.SwagSchema{Type: "boolean"}
+	case types.Int, types.Int64, types.Uint, types.Uint64, types.Uintptr:
+		return specs.SwagSchema{Type: "integer", Format: "int64"}
+	case types.Int8, types.Int16, types.Int32, types.Uint8, types.Uint16, types.Uint32:
+		return specs.SwagSchema{Type: "integer", Format: "int32"}
+	case types.Float32:
+		return specs.SwagSchema{Type: "number", Format: "float"}
+	case types.Float64:
+		return specs.SwagSchema{Type: "number", Format: "double"}
+	}
+	return specs.SwagSchema{}
+}
@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sfodje/swagson/specs"
+)
+
+func Test_typeRegistry(t *testing.T) {
+	pkgs, err := loadPackages(context.Background(), "../examples/", "", nil, &Diagnostics{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := newTypeRegistry()
+	registry.addPackage(pkgs[0], pkgs[0].Syntax)
+	defs, _, err := registry.schemas(registry.models)
+	if err != nil || len(defs) != 3 {
+		t.Fatalf("registry.schemas() returned %d definitions, expected 3.", len(defs))
+	}
+
+	order := defs["Order"]
+	props := *order.Properties
+	if order.Description != "An order placed for a pet in the store" || order.Type != "object" {
+		t.Error("registry.schemas() Order failed.")
+	}
+	if props["shipDate"].Format != "date-time" || props["status"].Type != "string" || props["status"].Description != "Order Status" {
+		t.Error("registry.schemas() Order field types failed.")
+	}
+	if props["customer"].Ref != "#/definitions/Customer" {
+		t.Error("registry.schemas() Order struct reference failed.")
+	}
+	if order.Required == nil || len(*order.Required) != 1 || (*order.Required)[0] != "complete" {
+		t.Error("registry.schemas() Order required fields failed.")
+	}
+
+	address := (*defs["Customer"].Properties)["address"]
+	if address.Type != "array" || address.Items.Ref != "#/definitions/Address" {
+		t.Error("registry.schemas() Customer slice reference failed.")
+	}
+	metadata := (*defs["Customer"].Properties)["metadata"]
+	if metadata.Type != "object" || metadata.AdditionalProperties == nil || metadata.AdditionalProperties.Schema.Type != "string" {
+		t.Error("registry.schemas() Customer map field failed.")
+	}
+	if (*defs["Address"].Properties)["zip"].Description != "postal code" {
+		t.Error("registry.schemas() Address line comment failed.")
+	}
+}
+
+func Test_typeRegistry_collision(t *testing.T) {
+	pkgs, err := loadPackages(context.Background(), "./testdata/models/", "", nil, &Diagnostics{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := newTypeRegistry()
+	for _, p := range pkgs {
+		registry.addPackage(p, p.Syntax)
+	}
+	_, _, err = registry.schemas(registry.models)
+	if err != nil && strings.HasSuffix(err.Error(), "b.go:5:6: api:model: definition Error of example.com/models/b.Error is already generated from example.com/models/a.Error at testdata/models/a/a.go:5:6") {
+		t.Log("registry.schemas(colliding names) passed.")
+	} else {
+		t.Log(err)
+		t.Error("registry.schemas(colliding names) failed.")
+	}
+
+	// each package is extracted on its own, the collision is found when their definitions are merged
+	_, diags := Build(context.Background(), Options{Dir: "./testdata/models/", SkipValidation: true})
+	if len(diags) > 0 && diags[0].Node == specs.APIMODEL.String() && strings.HasSuffix(diags[0].File, "b.go") &&
+		strings.HasSuffix(diags[0].Message, "is already generated from example.com/models/a.Error at testdata/models/a/a.go:5:6") {
+		t.Log("Build(colliding names) passed.")
+	} else {
+		t.Log(diags)
+		t.Error("Build(colliding names) failed.")
+	}
+}
@@ -0,0 +1,570 @@
+package generator
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/sfodje/swagson/specs"
+)
+
+// OpenAPI3 is the version string written to the openapi field of converted documents
+const OpenAPI3 = "3.0.3"
+
+// refPrefixes maps swagger 2.0 reference prefixes to their OpenAPI 3.0 locations
+var refPrefixes = [][2]string{
+	{"#/definitions/", "#/components/schemas/"},
+	{"#/parameters/", "#/components/parameters/"},
+	{"#/responses/", "#/components/responses/"},
+}
+
+// SwagDocToOpenAPI3 converts a swagger 2.0 document into an OpenAPI 3.0 document
+// Host, BasePath and Schemes become servers, definitions become components/schemas,
+// body and formData parameters become request bodies and response schemas are
+// listed once per media type the operation produces
+func SwagDocToOpenAPI3(source *specs.SwagDoc) (*specs.OpenAPIDoc, error) {
+	// references are rewritten in place, so work on a copy of the source document
+	var swagDoc = new(specs.SwagDoc)
+	j, err := json.Marshal(source)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(j, swagDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc = new(specs.OpenAPIDoc)
+	doc.OpenAPI = OpenAPI3
+	doc.Info = swagDoc.Info
+	doc.Tags = swagDoc.Tags
+	doc.ExternalDocs = swagDoc.ExternalDocs
+	doc.Servers = convertServers(swagDoc)
+	doc.Extensions = swagDoc.Extensions
+
+	doc.Security = swagDoc.Security
+
+	var paths = specs.OpenAPIPaths{Paths: make(map[string]specs.OpenAPIPathItem)}
+	if swagDoc.Paths != nil {
+		for name, path := range swagDoc.Paths.Paths {
+			item, err := convertPath(swagDoc, &path)
+			if err != nil {
+				return nil, err
+			}
+			paths.Paths[name] = *item
+		}
+		paths.Extensions = swagDoc.Paths.Extensions
+	}
+	doc.Paths = &paths
+
+	doc.Components, err = convertComponents(swagDoc)
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// convertServers builds the servers list from the Host, BasePath and Schemes fields
+func convertServers(swagDoc *specs.SwagDoc) *[]specs.OpenAPIServer {
+	if swagDoc.Host == "" {
+		if swagDoc.BasePath == "" {
+			return nil
+		}
+		return &[]specs.OpenAPIServer{{Url: swagDoc.BasePath}}
+	}
+	var schemes = []string{specs.HTTP.String()}
+	if swagDoc.Schemes != nil && len(*swagDoc.Schemes) > 0 {
+		schemes = *swagDoc.Schemes
+	}
+	var servers []specs.OpenAPIServer
+	for _, scheme := range schemes {
+		servers = append(servers, specs.OpenAPIServer{Url: scheme + "://" + swagDoc.Host + swagDoc.BasePath})
+	}
+	return &servers
+}
+
+// convertComponents moves the reusable swagger 2.0 objects into the OpenAPI 3.0 components object
+func convertComponents(swagDoc *specs.SwagDoc) (*specs.OpenAPIComponents, error) {
+	var components = new(specs.OpenAPIComponents)
+	var empty = true
+
+	if swagDoc.Definitions != nil {
+		var schemas = make(map[string]specs.SwagSchema)
+		for name, schema := range *swagDoc.Definitions {
+			rewriteSchemaRefs(&schema)
+			schemas[name] = schema
+		}
+		components.Schemas = &schemas
+		empty = false
+	}
+
+	if swagDoc.Parameters != nil {
+		var params = make(map[string]specs.OpenAPIParam)
+		var bodies = make(map[string]specs.OpenAPIRequestBody)
+		for name, param := range *swagDoc.Parameters {
+			if isBodyParam(&param) {
+				bodies[name] = *convertRequestBody([]specs.SwagParam{param}, mediaTypes(nil, swagDoc.Consumes))
+			} else {
+				params[name] = convertParam(&param)
+			}
+		}
+		if len(params) > 0 {
+			components.Parameters = &params
+		}
+		if len(bodies) > 0 {
+			components.RequestBodies = &bodies
+		}
+		empty = false
+	}
+
+	if swagDoc.Responses != nil {
+		var responses = make(map[string]specs.OpenAPIResponse)
+		for name, response := range *swagDoc.Responses {
+			r, err := convertResponse(&response, mediaTypes(nil, swagDoc.Produces))
+			if err != nil {
+				return nil, err
+			}
+			responses[name] = *r
+		}
+		components.Responses = &responses
+		empty = false
+	}
+
+	if swagDoc.SecurityDefinitions != nil {
+		var schemes = make(map[string]specs.OpenAPISecScheme)
+		for name, def := range *swagDoc.SecurityDefinitions {
+			schemes[name] = convertSecDef(&def)
+		}
+		components.SecuritySchemes = &schemes
+		empty = false
+	}
+
+	if empty {
+		return nil, nil
+	}
+	return components, nil
+}
+
+// convertPath converts a path item and each of its operations
+func convertPath(swagDoc *specs.SwagDoc, path *specs.SwagPath) (*specs.OpenAPIPathItem, error) {
+	var item = new(specs.OpenAPIPathItem)
+	item.Ref = path.Ref
+	item.Extensions = path.Extensions
+	if path.Parameters != nil {
+		var params []specs.OpenAPIParam
+		for _, p := range *path.Parameters {
+			if !isBodyParam(resolveParam(swagDoc, &p)) {
+				params = append(params, convertParam(&p))
+			}
+		}
+		if len(params) > 0 {
+			item.Parameters = &params
+		}
+	}
+
+	var ops = []struct {
+		dst **specs.OpenAPIOperation
+		src *specs.SwagOperation
+	}{
+		{&item.Get, path.Get},
+		{&item.Put, path.Put},
+		{&item.Post, path.Post},
+		{&item.Delete, path.Delete},
+		{&item.Options, path.Options},
+		{&item.Head, path.Head},
+		{&item.Patch, path.Patch},
+	}
+	for _, op := range ops {
+		if op.src == nil {
+			continue
+		}
+		converted, err := convertOperation(swagDoc, path, op.src)
+		if err != nil {
+			return nil, err
+		}
+		*op.dst = converted
+	}
+	return item, nil
+}
+
+// convertOperation converts a single operation, splitting its parameters into
+// OpenAPI 3.0 parameters and a request body
+func convertOperation(swagDoc *specs.SwagDoc, path *specs.SwagPath, op *specs.SwagOperation) (*specs.OpenAPIOperation, error) {
+	var converted = &specs.OpenAPIOperation{
+		Tags:         op.Tags,
+		Summary:      op.Summary,
+		Description:  op.Description,
+		ExternalDocs: op.ExternalDocs,
+		OperationId:  op.OperationId,
+		Deprecated:   op.Deprecated,
+		Security:     op.Security,
+		Extensions:   op.Extensions,
+	}
+
+	if op.Schemes != nil {
+		var servers []specs.OpenAPIServer
+		for _, scheme := range *op.Schemes {
+			servers = append(servers, specs.OpenAPIServer{Url: scheme + "://" + swagDoc.Host + swagDoc.BasePath})
+		}
+		converted.Servers = &servers
+	}
+
+	// non-body path level parameters stay on the path item, body parameters always move to the request body
+	var bodyParams []specs.SwagParam
+	var params []specs.OpenAPIParam
+	if path.Parameters != nil {
+		for _, p := range *path.Parameters {
+			if isBodyParam(resolveParam(swagDoc, &p)) {
+				bodyParams = append(bodyParams, p)
+			}
+		}
+	}
+	if op.Parameters != nil {
+		for _, p := range *op.Parameters {
+			if isBodyParam(resolveParam(swagDoc, &p)) {
+				bodyParams = append(bodyParams, p)
+			} else {
+				params = append(params, convertParam(&p))
+			}
+		}
+	}
+	if len(params) > 0 {
+		converted.Parameters = &params
+	}
+	if len(bodyParams) == 1 && bodyParams[0].Ref != "" {
+		// a shared body parameter was moved to components/requestBodies under the same name
+		name := strings.TrimPrefix(bodyParams[0].Ref, refPrefixes[1][0])
+		converted.RequestBody = &specs.OpenAPIRequestBody{Ref: "#/components/requestBodies/" + name}
+	} else if len(bodyParams) > 0 {
+		var resolved []specs.SwagParam
+		for _, p := range bodyParams {
+			resolved = append(resolved, *resolveParam(swagDoc, &p))
+		}
+		converted.RequestBody = convertRequestBody(resolved, mediaTypes(op.Consumes, swagDoc.Consumes))
+	}
+
+	var responses = specs.OpenAPIResponses{Responses: make(map[string]specs.OpenAPIResponse)}
+	if op.Responses != nil {
+		for code, response := range op.Responses.Responses {
+			r, err := convertResponse(&response, mediaTypes(op.Produces, swagDoc.Produces))
+			if err != nil {
+				return nil, err
+			}
+			responses.Responses[code] = *r
+		}
+		responses.Extensions = op.Responses.Extensions
+	}
+	converted.Responses = &responses
+	return converted, nil
+}
+
+// isBodyParam reports whether the parameter becomes part of a request body in OpenAPI 3.0
+func isBodyParam(p *specs.SwagParam) bool {
+	return p.In == specs.BODY.String() || p.In == specs.FORMDATA.String()
+}
+
+// resolveParam returns the shared parameter a parameter refers to, or the parameter itself when it is not a reference
+func resolveParam(swagDoc *specs.SwagDoc, p *specs.SwagParam) *specs.SwagParam {
+	if p.Ref == "" || swagDoc.Parameters == nil {
+		return p
+	}
+	if shared, ok := (*swagDoc.Parameters)[strings.TrimPrefix(p.Ref, refPrefixes[1][0])]; ok {
+		return &shared
+	}
+	return p
+}
+
+// mediaTypes returns the operation level media types, falling back to the document level ones
+// and finally to application/json
+func mediaTypes(op *[]string, doc *[]string) []string {
+	if op != nil && len(*op) > 0 {
+		return *op
+	}
+	if doc != nil && len(*doc) > 0 {
+		return *doc
+	}
+	return []string{"application/json"}
+}
+
+// convertParam converts a non-body parameter, moving its type information into a schema
+func convertParam(p *specs.SwagParam) specs.OpenAPIParam {
+	if p.Ref != "" {
+		return specs.OpenAPIParam{Ref: rewriteRef(p.Ref)}
+	}
+	var schema = p.Schema
+	if schema == nil {
+		schema = itemsToSchema(&specs.SwagItems{
+			Type:             p.Type,
+			Format:           p.Format,
+			Items:            p.Items,
+			Default:          p.Default,
+			Maximum:          p.Maximum,
+			ExclusiveMaximum: p.ExclusiveMaximum,
+			Minimum:          p.Minimum,
+			ExclusiveMinimum: p.ExclusiveMinimum,
+			MaxLength:        p.MaxLength,
+			MinLength:        p.MinLength,
+			Pattern:          p.Pattern,
+			MaxItems:         p.MaxItems,
+			MinItems:         p.MinItems,
+			UniqueItems:      p.UniqueItems,
+			Enum:             p.Enum,
+			MultipleOf:       p.MultipleOf,
+		})
+	}
+	rewriteSchemaRefs(schema)
+
+	var param = specs.OpenAPIParam{
+		Name:            p.Name,
+		In:              p.In,
+		Description:     p.Description,
+		Required:        p.Required,
+		AllowEmptyValue: p.AllowEmptyValue,
+		Schema:          schema,
+		Extensions:      p.Extensions,
+	}
+	if p.CollectionFormat != nil {
+		var explode = false
+		switch *p.CollectionFormat {
+		case specs.CSV:
+			if p.In == specs.QUERY.String() {
+				param.Style = "form"
+			} else {
+				param.Style = "simple"
+			}
+		case specs.SSV:
+			param.Style = "spaceDelimited"
+		case specs.PIPES:
+			param.Style = "pipeDelimited"
+		case specs.MULTI:
+			// repeated query parameters are the default form style of OpenAPI 3.0
+			param.Style = "form"
+			explode = true
+		}
+		if param.Style != "" {
+			param.Explode = &explode
+		}
+	}
+	return param
+}
+
+// convertRequestBody converts body and formData parameters into a single request body
+// formData parameters are collected into the properties of one object schema
+func convertRequestBody(params []specs.SwagParam, consumes []string) *specs.OpenAPIRequestBody {
+	var body = new(specs.OpenAPIRequestBody)
+	var content = make(map[string]specs.OpenAPIMediaType)
+
+	var form = specs.SwagSchema{Type: "object"}
+	var properties = make(map[string]specs.SwagSchema)
+	var required []string
+	var multipart = false
+	for _, p := range params {
+		if p.In == specs.BODY.String() {
+			body.Description = p.Description
+			body.Required = p.Required != nil && *p.Required
+			body.Extensions = p.Extensions
+			schema := p.Schema
+			if schema == nil {
+				schema = new(specs.SwagSchema)
+			}
+			rewriteSchemaRefs(schema)
+			for _, mime := range consumes {
+				content[mime] = specs.OpenAPIMediaType{Schema: schema}
+			}
+			continue
+		}
+		converted := convertParam(&p)
+		if p.Type == "file" {
+			converted.Schema.Type = "string"
+			converted.Schema.Format = "binary"
+			multipart = true
+		}
+		converted.Schema.Description = p.Description
+		properties[p.Name] = *converted.Schema
+		if p.Required != nil && *p.Required {
+			required = append(required, p.Name)
+			body.Required = true
+		}
+	}
+
+	if len(properties) > 0 {
+		form.Properties = &properties
+		if len(required) > 0 {
+			form.Required = &required
+		}
+		mime := "application/x-www-form-urlencoded"
+		for _, c := range consumes {
+			if c == "multipart/form-data" {
+				multipart = true
+			}
+		}
+		if multipart {
+			mime = "multipart/form-data"
+		}
+		content[mime] = specs.OpenAPIMediaType{Schema: &form}
+	}
+
+	body.Content = &content
+	return body
+}
+
+// convertResponse converts a response, listing its schema once for every media type produced
+func convertResponse(response *specs.SwagResponse, produces []string) (*specs.OpenAPIResponse, error) {
+	if response.Ref != "" {
+		return &specs.OpenAPIResponse{Ref: rewriteRef(response.Ref)}, nil
+	}
+	var converted = &specs.OpenAPIResponse{Description: response.Description, Extensions: response.Extensions}
+	if response.Schema != nil {
+		rewriteSchemaRefs(response.Schema)
+		// swagger 2.0 describes file downloads with the file type, OpenAPI 3.0 with binary strings
+		if response.Schema.Type == "file" {
+			response.Schema.Type = "string"
+			response.Schema.Format = "binary"
+		}
+
+		var content = make(map[string]specs.OpenAPIMediaType)
+		for _, mime := range produces {
+			var media = specs.OpenAPIMediaType{Schema: response.Schema}
+			if response.Examples != nil {
+				if example, ok := (*response.Examples)[mime]; ok {
+					media.Example = example
+				}
+			}
+			content[mime] = media
+		}
+		converted.Content = &content
+	}
+	if response.Headers != nil {
+		var headers = make(map[string]specs.OpenAPIHeader)
+		for name, header := range *response.Headers {
+			headers[name] = specs.OpenAPIHeader{
+				Description: header.Description,
+				Schema: itemsToSchema(&specs.SwagItems{
+					Type:             header.Type,
+					Format:           header.Format,
+					Items:            header.Items,
+					Default:          header.Default,
+					Maximum:          header.Maximum,
+					ExclusiveMaximum: header.ExclusiveMaximum,
+					Minimum:          header.Minimum,
+					ExclusiveMinimum: header.ExclusiveMinimum,
+					MaxLength:        header.MaxLength,
+					MinLength:        header.MinLength,
+					Pattern:          header.Pattern,
+					MaxItems:         header.MaxItems,
+					MinItems:         header.MinItems,
+					UniqueItems:      header.UniqueItems,
+					Enum:             header.Enum,
+					MultipleOf:       header.MultipleOf,
+				}),
+				Extensions: header.Extensions,
+			}
+		}
+		converted.Headers = &headers
+	}
+	return converted, nil
+}
+
+// convertSecDef converts a security definition into a security scheme
+func convertSecDef(def *specs.SwagSecDef) specs.OpenAPISecScheme {
+	var scheme = specs.OpenAPISecScheme{
+		Type:        def.Type,
+		Description: def.Description,
+		Extensions:  def.Extensions,
+	}
+	switch def.Type {
+	case specs.BASIC.String():
+		scheme.Type = "http"
+		scheme.Scheme = "basic"
+	case specs.APIKEY.String():
+		scheme.Name = def.Name
+		scheme.In = def.In
+	case specs.OAUTH2.String():
+		var flow = &specs.OpenAPIOAuthFlow{
+			AuthorizationUrl: def.AuthorizationUrl,
+			TokenUrl:         def.TokenUrl,
+			Scopes:           def.Scopes,
+		}
+		if flow.Scopes == nil {
+			flow.Scopes = &map[string]string{}
+		}
+		var flows = new(specs.OpenAPIOAuthFlows)
+		switch def.Flow {
+		case "implicit":
+			flows.Implicit = flow
+		case "password":
+			flows.Password = flow
+		case "application":
+			flows.ClientCredentials = flow
+		case "accessCode":
+			flows.AuthorizationCode = flow
+		}
+		scheme.Flows = flows
+	}
+	return scheme
+}
+
+// itemsToSchema converts an items object (used by headers and non-body parameters) into a schema
+func itemsToSchema(items *specs.SwagItems) *specs.SwagSchema {
+	var schema = &specs.SwagSchema{
+		Ref:              items.Ref,
+		Description:      items.Description,
+		Type:             items.Type,
+		Format:           items.Format,
+		Default:          items.Default,
+		Maximum:          items.Maximum,
+		ExclusiveMaximum: items.ExclusiveMaximum,
+		Minimum:          items.Minimum,
+		ExclusiveMinimum: items.ExclusiveMinimum,
+		MaxLength:        items.MaxLength,
+		MinLength:        items.MinLength,
+		Pattern:          items.Pattern,
+		MaxItems:         items.MaxItems,
+		MinItems:         items.MinItems,
+		UniqueItems:      items.UniqueItems,
+		Enum:             items.Enum,
+		MultipleOf:       items.MultipleOf,
+	}
+	if items.Items != nil {
+		schema.Items = itemsToSchema(items.Items)
+	}
+	rewriteSchemaRefs(schema)
+	return schema
+}
+
+// rewriteSchemaRefs points every $ref in the schema at its OpenAPI 3.0 components location
+// discriminators are turned into Discriminator Objects along the way
+func rewriteSchemaRefs(schema *specs.SwagSchema) {
+	schema.Ref = rewriteRef(schema.Ref)
+	if schema.Discriminator != nil {
+		discriminator := *schema.Discriminator
+		discriminator.Object = true
+		schema.Discriminator = &discriminator
+	}
+	if schema.Properties != nil {
+		for name, property := range *schema.Properties {
+			rewriteSchemaRefs(&property)
+			(*schema.Properties)[name] = property
+		}
+	}
+	if schema.Items != nil {
+		rewriteSchemaRefs(schema.Items)
+	}
+	if schema.AllOf != nil {
+		for i := range *schema.AllOf {
+			rewriteSchemaRefs(&(*schema.AllOf)[i])
+		}
+	}
+	if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+		rewriteSchemaRefs(schema.AdditionalProperties.Schema)
+	}
+}
+
+// rewriteRef converts a single swagger 2.0 reference
+func rewriteRef(ref string) string {
+	for _, prefix := range refPrefixes {
+		if strings.HasPrefix(ref, prefix[0]) {
+			return prefix[1] + strings.TrimPrefix(ref, prefix[0])
+		}
+	}
+	return ref
+}
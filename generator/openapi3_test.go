@@ -0,0 +1,128 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/sfodje/swagson/specs"
+)
+
+func Test_swagDocToOpenAPI3(t *testing.T) {
+	var markup map[specs.MarkupNode][]markupDoc = make(map[specs.MarkupNode][]markupDoc)
+	for _, file := range []string{"../examples/api_meta.go", "../examples/api_route.go", "../examples/api_model.go"} {
+		extractMarkup(markup, extractComments(parseExample(t, file)))
+	}
+	swagDoc, err := extractSwaggerDoc(&markup)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := SwagDocToOpenAPI3(swagDoc)
+	if err != nil || doc.OpenAPI != OpenAPI3 {
+		t.Log(err)
+		t.Fatal("SwagDocToOpenAPI3(swagDoc) failed.")
+	}
+
+	if doc.Servers == nil || (*doc.Servers)[0].Url != "http://petstore.swagger.io/v2" {
+		t.Error("SwagDocToOpenAPI3(swagDoc) servers failed.")
+	}
+	if doc.Components == nil || doc.Components.Schemas == nil || (*doc.Components.Schemas)["Pet"].Type != "object" {
+		t.Error("SwagDocToOpenAPI3(swagDoc) components/schemas failed.")
+	}
+	if scheme := (*doc.Components.SecuritySchemes)["petstore_auth"]; scheme.Flows == nil || scheme.Flows.Implicit == nil {
+		t.Error("SwagDocToOpenAPI3(swagDoc) securitySchemes failed.")
+	}
+
+	get := doc.Paths.Paths["/pet/{petId}"].Get
+	if get == nil || get.Parameters == nil || (*get.Parameters)[0].Schema.Type != "integer" {
+		t.Fatal("SwagDocToOpenAPI3(swagDoc) parameters failed.")
+	}
+	content := *get.Responses.Responses["200"].Content
+	if len(content) != 2 || content["application/json"].Schema.Ref != "#/components/schemas/Pet" {
+		t.Error("SwagDocToOpenAPI3(swagDoc) response content failed.")
+	}
+	if get.Responses.Responses["404"].Ref != "#/components/responses/NotFound" {
+		t.Error("SwagDocToOpenAPI3(swagDoc) response $ref failed.")
+	}
+	inventory := doc.Paths.Paths["/store/inventory"].Get
+	if inventory.Parameters == nil || (*inventory.Parameters)[0].Ref != "#/components/parameters/limit" {
+		t.Error("SwagDocToOpenAPI3(swagDoc) parameter $ref failed.")
+	}
+	if swagDoc.Paths.Paths["/pet/{petId}"].Get.Responses == nil {
+		t.Error("SwagDocToOpenAPI3(swagDoc) modified its source document.")
+	}
+}
+
+func Test_convertRequestBody(t *testing.T) {
+	var required = true
+	params := []specs.SwagParam{
+		{Name: "body", In: "body", Required: &required, Schema: &specs.SwagSchema{Ref: "#/definitions/Pet"}},
+	}
+	body := convertRequestBody(params, []string{"application/json", "application/xml"})
+	if len(*body.Content) == 2 && body.Required && (*body.Content)["application/xml"].Schema.Ref == "#/components/schemas/Pet" {
+		t.Log("convertRequestBody(body params) passed.")
+	} else {
+		t.Error("convertRequestBody(body params) failed.")
+	}
+
+	params = []specs.SwagParam{
+		{Name: "name", In: "formData", Type: "string", Required: &required},
+		{Name: "file", In: "formData", Type: "file"},
+	}
+	body = convertRequestBody(params, []string{"application/x-www-form-urlencoded"})
+	form, ok := (*body.Content)["multipart/form-data"]
+	if ok && len(*form.Schema.Properties) == 2 && (*form.Schema.Properties)["file"].Format == "binary" {
+		t.Log("convertRequestBody(formData params) passed.")
+	} else {
+		t.Error("convertRequestBody(formData params) failed.")
+	}
+}
+
+func Test_convertResponse(t *testing.T) {
+	var responses map[string]specs.SwagResponse
+	doc := markupDoc{Node: specs.APIRESPONSE, Text: `PetList:
+    Description: a page of pets
+    Schema:
+        Type: array
+        Items:
+            $ref: "#/definitions/Pet"
+    Headers:
+        X-Rate-Limit:
+            Type: integer
+            Format: int32
+    Examples:
+        application/json:
+            - {id: 1, name: doggie}`}
+	if err := doc.decode(&responses); err != nil {
+		t.Fatal(err)
+	}
+	list := responses["PetList"]
+	if list.Schema != nil && list.Schema.Items.Ref == "#/definitions/Pet" && (*list.Headers)["X-Rate-Limit"].Format == "int32" {
+		t.Log("decode(typed response) passed.")
+	} else {
+		t.Error("decode(typed response) failed.")
+	}
+
+	converted, err := convertResponse(&list, []string{"application/json", "application/xml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := *converted.Content
+	example, err := DocToJson(content["application/json"].Example)
+	if err == nil && content["application/json"].Schema.Items.Ref == "#/components/schemas/Pet" && string(*example) == `[{"id":1,"name":"doggie"}]` &&
+		content["application/xml"].Example == nil && (*converted.Headers)["X-Rate-Limit"].Schema.Type == "integer" {
+		t.Log("convertResponse(typed response) passed.")
+	} else {
+		t.Log(err)
+		t.Error("convertResponse(typed response) failed.")
+	}
+
+	download := specs.SwagResponse{Description: "the pet photo", Schema: &specs.SwagSchema{Type: "file"}}
+	converted, err = convertResponse(&download, []string{"application/octet-stream"})
+	schema := (*converted.Content)["application/octet-stream"].Schema
+	if err == nil && schema.Type == "string" && schema.Format == "binary" {
+		t.Log("convertResponse(file response) passed.")
+	} else {
+		t.Log(err, schema)
+		t.Error("convertResponse(file response) failed.")
+	}
+}
@@ -0,0 +1,151 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Options configures a run of the generator
+type Options struct {
+	// Dir is the root directory of the go project, every package below it is loaded
+	Dir string
+	// Package restricts the run to the packages with this name when it is not empty
+	Package string
+	// Include restricts the run to the files matching at least one of these globs when it is not empty
+	Include []string
+	// Exclude skips the files matching any of these globs
+	Exclude []string
+	// Tags are the build tags files are selected with, like the -tags flag of go build
+	Tags []string
+	// SkipValidation disables the check of the document against the swagger 2.0 schema
+	SkipValidation bool
+	// Strict reports markup keys that match no field of the swagger objects as errors instead of warnings
+	Strict bool
+	// Workers is the number of files markup is extracted from at once, one per CPU when not set
+	Workers int
+	// Stats is filled in with the counts and timings of the run when it is not nil
+	Stats *Stats
+	// CacheDir is the directory the markup of the project is cached in between runs, nothing is cached when it is empty
+	CacheDir string
+}
+
+// IGNOREDDIRS are the directories whose files are never selected, along with directories starting with . or _
+// the go tool leaves them out of ./... patterns, node_modules is added for projects that ship a web frontend
+var IGNOREDDIRS = []string{"vendor", "testdata", "node_modules"}
+
+// selectFiles returns the syntax trees of the package files selected by the include and exclude globs
+// globs are matched against the slash separated path of a file relative to Dir, a ** segment matches any number of directories.
+// Files in ignored directories, test files and generated files are skipped whatever the globs.
+// The files are returned sorted by name whatever order the go tool listed them in.
+func (opts Options) selectFiles(pkg *packages.Package) ([]*ast.File, error) {
+	dir, err := filepath.Abs(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []*ast.File
+	for _, f := range pkg.Syntax {
+		selected, err := opts.selectFile(dir, pkg.Fset.Position(f.Pos()).Filename)
+		if err != nil {
+			return nil, err
+		}
+		if selected && !ast.IsGenerated(f) {
+			files = append(files, f)
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return pkg.Fset.File(files[i].Pos()).Name() < pkg.Fset.File(files[j].Pos()).Name()
+	})
+	return files, nil
+}
+
+// selectFile reports whether the file at filename is selected by the include and exclude globs
+// dir is the absolute path of Dir, generated files are left to the caller since telling them apart takes parsing the file.
+func (opts Options) selectFile(dir string, filename string) (bool, error) {
+	rel, err := filepath.Rel(dir, filename)
+	if err != nil {
+		return false, err
+	}
+	rel = filepath.ToSlash(rel)
+	if ignored(rel) {
+		return false, nil
+	}
+	included, err := matchAny(opts.Include, rel)
+	if err != nil {
+		return false, err
+	}
+	excluded, err := matchAny(opts.Exclude, rel)
+	if err != nil {
+		return false, err
+	}
+	return (len(opts.Include) == 0 || included) && !excluded, nil
+}
+
+// ignored reports whether the file is a test file or lies in a directory the go tool ignores
+func ignored(name string) bool {
+	if strings.HasSuffix(name, "_test.go") {
+		return true
+	}
+	segments := strings.Split(name, "/")
+	for _, dir := range segments[:len(segments)-1] {
+		if ignoredDir(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoredDir reports whether the go tool ignores the directory with the given name
+func ignoredDir(name string) bool {
+	if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+		return true
+	}
+	for _, ignored := range IGNOREDDIRS {
+		if name == ignored {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAny reports whether name matches any of the globs
+func matchAny(globs []string, name string) (bool, error) {
+	for _, glob := range globs {
+		ok, err := matchGlob(strings.Split(glob, "/"), strings.Split(name, "/"))
+		if err != nil {
+			return false, fmt.Errorf("invalid glob %q: %v", glob, err)
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// matchGlob matches path segments against glob segments, ** matches zero or more segments
+func matchGlob(glob []string, name []string) (bool, error) {
+	for len(glob) > 0 {
+		if glob[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if ok, err := matchGlob(glob[1:], name[i:]); ok || err != nil {
+					return ok, err
+				}
+			}
+			return false, nil
+		}
+		if len(name) == 0 {
+			return false, nil
+		}
+		ok, err := path.Match(glob[0], name[0])
+		if !ok || err != nil {
+			return false, err
+		}
+		glob, name = glob[1:], name[1:]
+	}
+	return len(name) == 0, nil
+}
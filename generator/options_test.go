@@ -0,0 +1,109 @@
+package generator
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func Test_matchAny(t *testing.T) {
+	var tests = []struct {
+		globs    []string
+		name     string
+		expected bool
+	}{
+		{[]string{"*.go"}, "api.go", true},
+		{[]string{"*.go"}, "internal/api.go", false},
+		{[]string{"**/*.go"}, "internal/api.go", true},
+		{[]string{"**/*.go"}, "api.go", true},
+		{[]string{"internal/**"}, "internal/v1/api.go", true},
+		{[]string{"cmd/*", "internal/*_mock.go"}, "internal/store_mock.go", true},
+		{[]string{"cmd/*"}, "internal/store.go", false},
+	}
+	for _, test := range tests {
+		if ok, err := matchAny(test.globs, test.name); err == nil && ok == test.expected {
+			t.Logf("matchAny(%q, %q) passed.", test.globs, test.name)
+		} else {
+			t.Log(err)
+			t.Errorf("matchAny(%q, %q) failed.", test.globs, test.name)
+		}
+	}
+
+	if _, err := matchAny([]string{"[a-"}, "api.go"); err != nil {
+		t.Log("matchAny(invalid glob) passed.")
+	} else {
+		t.Error("matchAny(invalid glob) failed.")
+	}
+}
+
+func Test_Options_selectFiles(t *testing.T) {
+	pkgs, err := loadPackages(context.Background(), "../examples/", "", nil, &Diagnostics{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := Options{Dir: "../examples", Include: []string{"api_*.go"}, Exclude: []string{"api_struct.go", "api_model.go"}}
+	files, err := opts.selectFiles(pkgs[0])
+	if err == nil && len(files) == 2 {
+		t.Log("Options.selectFiles(include, exclude) passed.")
+	} else {
+		t.Log(err, len(files))
+		t.Error("Options.selectFiles(include, exclude) failed.")
+	}
+}
+
+// selectedFiles returns the slash separated paths, relative to opts.Dir, of the files selected in every package
+func selectedFiles(t *testing.T, opts Options) []string {
+	pkgs, err := loadPackages(context.Background(), opts.Dir, "", opts.Tags, &Diagnostics{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, _ := filepath.Abs(opts.Dir)
+	var names []string
+	for _, p := range pkgs {
+		files, err := opts.selectFiles(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range files {
+			rel, _ := filepath.Rel(dir, p.Fset.Position(f.Pos()).Filename)
+			names = append(names, filepath.ToSlash(rel))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func Test_Options_selectFiles_ignored(t *testing.T) {
+	var tests = []struct {
+		opts     Options
+		expected []string
+	}{
+		{Options{Dir: "./testdata/files"}, []string{"api.go", "internal/store.go"}},
+		{Options{Dir: "./testdata/files", Tags: []string{"integration"}}, []string{"api.go", "api_integration.go", "internal/store.go"}},
+		{Options{Dir: "./testdata/files", Exclude: []string{"internal/**"}}, []string{"api.go"}},
+		{Options{Dir: "./testdata/files", Include: []string{"**/*.go"}}, []string{"api.go", "internal/store.go"}},
+	}
+	for _, test := range tests {
+		if files := selectedFiles(t, test.opts); reflect.DeepEqual(files, test.expected) {
+			t.Logf("Options.selectFiles(%+v) passed.", test.opts)
+		} else {
+			t.Log(files)
+			t.Errorf("Options.selectFiles(%+v) failed.", test.opts)
+		}
+	}
+
+	for _, name := range []string{"node_modules/widget/widget.go", "vendor/example.com/dep/dep.go", "_build/api.go", ".cache/api.go", "api_test.go"} {
+		if ignored(name) {
+			t.Logf("ignored(%q) passed.", name)
+		} else {
+			t.Errorf("ignored(%q) failed.", name)
+		}
+	}
+	if !ignored("internal/store.go") {
+		t.Log("ignored(\"internal/store.go\") passed.")
+	} else {
+		t.Error("ignored(\"internal/store.go\") failed.")
+	}
+}
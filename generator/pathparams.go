@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sfodje/swagson/specs"
+)
+
+// templateParam matches the variables of a path template such as /pet/{petId}
+var templateParam = regexp.MustCompile(`\{([^}/]+)\}`)
+
+// checkPathParams cross-checks the api:route comments written on handler functions against their path templates
+// a template variable without a path parameter is a warning and a required string path parameter is added for it,
+// a path parameter that is not part of the template is an error
+func checkPathParams(swagDoc *specs.SwagDoc, docs []markupDoc) ([]error, error) {
+	var warnings []error
+	var errs errorList
+	if swagDoc.Paths == nil {
+		return nil, nil
+	}
+	for _, doc := range docs {
+		if doc.Handler == "" {
+			continue
+		}
+		docPaths, err := routePaths(doc)
+		if err != nil {
+			continue
+		}
+		var names []string
+		for name := range docPaths.Paths {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			var template = make(map[string]bool)
+			var variables []string
+			for _, match := range templateParam.FindAllStringSubmatch(name, -1) {
+				template[match[1]] = true
+				variables = append(variables, match[1])
+			}
+
+			declared := docPaths.Paths[name]
+			path := swagDoc.Paths.Paths[name]
+			for _, verb := range mergePath(&specs.SwagPath{}, &declared) {
+				op := *verbOperation(&path, verb)
+				var params = pathParams(swagDoc, path.Parameters, op.Parameters)
+				for _, variable := range variables {
+					if params[variable] {
+						continue
+					}
+					warnings = append(warnings, doc.errorf("path parameter %s of %s %s is missing from Parameters, declaring it as a required string",
+						variable, strings.ToUpper(verb), name))
+					var list []specs.SwagParam
+					if op.Parameters != nil {
+						list = append(list, *op.Parameters...)
+					}
+					var required = true
+					list = append(list, specs.SwagParam{Name: variable, In: specs.PATH.String(), Required: &required, Type: "string"})
+					op.Parameters = &list
+				}
+
+				var undeclared []string
+				for param := range params {
+					if !template[param] {
+						undeclared = append(undeclared, param)
+					}
+				}
+				sort.Strings(undeclared)
+				for _, param := range undeclared {
+					errs.add(doc.errorf("path parameter %s of %s %s is not part of the path template", param, strings.ToUpper(verb), name))
+				}
+			}
+		}
+	}
+	return warnings, errs.err()
+}
+
+// pathParams returns the names of the path parameters declared on a path item and one of its operations
+func pathParams(swagDoc *specs.SwagDoc, lists ...*[]specs.SwagParam) map[string]bool {
+	var names = make(map[string]bool)
+	for _, list := range lists {
+		if list == nil {
+			continue
+		}
+		for _, p := range *list {
+			if param := resolveParam(swagDoc, &p); param.In == specs.PATH.String() {
+				names[param.Name] = true
+			}
+		}
+	}
+	return names
+}
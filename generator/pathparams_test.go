@@ -0,0 +1,62 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/sfodje/swagson/specs"
+)
+
+func Test_checkPathParams(t *testing.T) {
+	var markup = map[specs.MarkupNode][]markupDoc{
+		specs.APIROUTE: {
+			{Node: specs.APIROUTE, File: "pet.go", Line: 2, Column: 1, Handler: "updatePet", Text: `"/pet/{petId}/photos/{photoId}":
+    Post:
+        Parameters:
+            - Name: petId
+              In: path
+              Required: true
+              Type: integer
+            - Name: ownerId
+              In: path
+              Required: true
+              Type: integer
+        Responses:
+            200:
+                Description: successful operation`},
+			{Node: specs.APIROUTE, File: "store.go", Line: 2, Column: 1, Text: `"/store/{storeId}":
+    Get:
+        Responses:
+            200:
+                Description: successful operation`},
+		},
+	}
+	swagDoc, err := extractSwaggerDoc(&markup)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	warnings, err := checkPathParams(swagDoc, markup[specs.APIROUTE])
+	if len(warnings) == 1 && warnings[0].Error() == "pet.go:2:1: api:route: path parameter photoId of POST /pet/{petId}/photos/{photoId} is missing from Parameters, declaring it as a required string" {
+		t.Log("checkPathParams(missing template variable) passed.")
+	} else {
+		t.Log(warnings)
+		t.Error("checkPathParams(missing template variable) failed.")
+	}
+	if err != nil && err.Error() == "pet.go:2:1: api:route: path parameter ownerId of POST /pet/{petId}/photos/{photoId} is not part of the path template" {
+		t.Log("checkPathParams(parameter outside the template) passed.")
+	} else {
+		t.Log(err)
+		t.Error("checkPathParams(parameter outside the template) failed.")
+	}
+
+	params := *swagDoc.Paths.Paths["/pet/{petId}/photos/{photoId}"].Post.Parameters
+	if added := params[len(params)-1]; added.Name == "photoId" && added.In == "path" && added.Required != nil && *added.Required && added.Type == "string" {
+		t.Log("checkPathParams(added parameter) passed.")
+	} else {
+		t.Log(params)
+		t.Error("checkPathParams(added parameter) failed.")
+	}
+	if swagDoc.Paths.Paths["/store/{storeId}"].Get.Parameters != nil {
+		t.Error("checkPathParams(comment without handler) failed.")
+	}
+}
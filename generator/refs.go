@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/sfodje/swagson/specs"
+)
+
+// checkRefs resolves every local $ref declared in the markup against the assembled document
+// and returns an error positioned at each reference that points at nothing. Remote references are not followed,
+// markup that cannot be decoded is skipped since the decode error is reported when the document is assembled.
+func checkRefs(swagDoc *specs.SwagDoc, markup map[specs.MarkupNode][]markupDoc) ([]error, error) {
+	j, err := json.Marshal(swagDoc)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	err = json.Unmarshal(j, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var refErrors []error
+	for _, node := range NODES {
+		for _, m := range markup[node] {
+			var body interface{}
+			if m.decode(&body) != nil {
+				continue
+			}
+
+			var seen = make(map[string]bool)
+			for _, ref := range collectRefs(body, nil) {
+				if seen[ref] || !strings.HasPrefix(ref, "#") || resolvePointer(doc, strings.TrimPrefix(ref, "#")) {
+					continue
+				}
+				seen[ref] = true
+				for _, offset := range refLines(m, ref) {
+					refErrors = append(refErrors, m.errorAt(offset, "unresolved $ref %q", ref))
+				}
+			}
+		}
+	}
+	return refErrors, nil
+}
+
+// collectRefs appends the value of every $ref key found in the decoded json value to refs
+func collectRefs(v interface{}, refs []string) []string {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, child := range t {
+			if ref, ok := child.(string); ok && k == "$ref" {
+				refs = append(refs, ref)
+				continue
+			}
+			refs = collectRefs(child, refs)
+		}
+	case []interface{}:
+		for _, child := range t {
+			refs = collectRefs(child, refs)
+		}
+	}
+	return refs
+}
+
+// resolvePointer reports whether the JSON pointer points at a value in the decoded document
+func resolvePointer(doc interface{}, pointer string) bool {
+	if pointer == "" {
+		return true
+	}
+	for _, segment := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		segment = strings.Replace(segment, "~1", "/", -1)
+		segment = strings.Replace(segment, "~0", "~", -1)
+		switch t := doc.(type) {
+		case map[string]interface{}:
+			child, ok := t[segment]
+			if !ok {
+				return false
+			}
+			doc = child
+		case []interface{}:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(t) {
+				return false
+			}
+			doc = t[i]
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// refLines returns the line offsets of every $ref to the given target within the markup body
+// the start of the body is returned when the reference cannot be found in the raw text
+func refLines(m markupDoc, ref string) []int {
+	var lines []int
+	for i, line := range strings.Split(m.Text, "\n") {
+		if strings.Contains(line, "$ref") && strings.Contains(line, ref) {
+			lines = append(lines, i)
+		}
+	}
+	if len(lines) == 0 {
+		return []int{0}
+	}
+	return lines
+}
@@ -0,0 +1,67 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/sfodje/swagson/specs"
+)
+
+func Test_checkRefs(t *testing.T) {
+	var markup = map[specs.MarkupNode][]markupDoc{
+		specs.APIROUTE: {
+			{Node: specs.APIROUTE, File: "pet.go", Line: 10, Column: 1, Text: `/pet:
+    Post:
+        Parameters:
+            - In: body
+              Name: body
+              Schema:
+                  $ref: "#/definitions/Pet"
+        Responses:
+            200:
+                Description: successful operation
+                Schema:
+                    $ref: "#/definitions/Order"`},
+		},
+		specs.APIMODEL: {
+			{Node: specs.APIMODEL, File: "models.go", Line: 3, Column: 1, Text: `Pet:
+    Type: object
+    Properties:
+        category:
+            $ref: "#/definitions/Category"
+        external:
+            $ref: "http://example.com/schemas/External"`},
+		},
+	}
+	swagDoc, err := extractSwaggerDoc(&markup)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refErrors, err := checkRefs(swagDoc, markup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found = make(map[string]bool)
+	for _, e := range refErrors {
+		found[e.Error()] = true
+	}
+	if len(refErrors) == 2 && found[`pet.go:21:1: api:route: unresolved $ref "#/definitions/Order"`] && found[`models.go:7:1: api:model: unresolved $ref "#/definitions/Category"`] {
+		t.Log("checkRefs(swagDoc, markup) passed.")
+	} else {
+		t.Log(refErrors)
+		t.Error("checkRefs(swagDoc, markup) failed.")
+	}
+}
+
+func Test_resolvePointer(t *testing.T) {
+	var doc = map[string]interface{}{
+		"paths": map[string]interface{}{
+			"/pet/{petId}": map[string]interface{}{"parameters": []interface{}{"id"}},
+		},
+	}
+	if resolvePointer(doc, "/paths/~1pet~1{petId}/parameters/0") && !resolvePointer(doc, "/paths/~1pet/parameters") && !resolvePointer(doc, "/paths/~1pet~1{petId}/parameters/1") {
+		t.Log("resolvePointer(doc, pointer) passed.")
+	} else {
+		t.Error("resolvePointer(doc, pointer) failed.")
+	}
+}
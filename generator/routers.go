@@ -0,0 +1,643 @@
+package generator
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sfodje/swagson/specs"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// route is a route registration found in the go source, tying a path and method to a handler function
+// Method is empty when the router accepts every method on the path
+type route struct {
+	Method      string
+	Path        string
+	Handler     string
+	HandlerFile string
+	Pos         token.Position
+	// Unprefixed is set when the route is registered on a router whose path prefix could not be followed
+	Unprefixed bool
+}
+
+// VERBS are the operations of a path item, in the order they appear in the specification
+var VERBS = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// router describes how a router package registers routes
+// methods maps a registration function to the method it registers, "" when the method is an argument
+// or comes from a chained call. method, path and handler are the argument indexes of the method, path
+// and handler; a negative method means the call has no method argument, a negative handler counts from the last argument
+type router struct {
+	pkg     string
+	methods map[string]string
+	method  int
+	path    int
+	handler int
+}
+
+// verbFuncs maps the registration functions named after a verb, GET or Get, to their method
+func verbFuncs(upper bool) map[string]string {
+	var funcs = make(map[string]string)
+	for _, verb := range VERBS {
+		name := strings.ToUpper(verb[:1]) + verb[1:]
+		if upper {
+			name = strings.ToUpper(verb)
+		}
+		funcs[name] = strings.ToUpper(verb)
+	}
+	return funcs
+}
+
+// ROUTERS are the route registration functions swagson recognizes
+// gorilla/mux takes its methods from a chained Methods call, net/http from the Go 1.22 pattern syntax
+var ROUTERS = []router{
+	{pkg: "net/http", methods: map[string]string{"Handle": "", "HandleFunc": ""}, method: -1, path: 0, handler: 1},
+	{pkg: "github.com/gorilla/mux", methods: map[string]string{"Handle": "", "HandleFunc": ""}, method: -1, path: 0, handler: 1},
+	{pkg: "github.com/go-chi/chi", methods: verbFuncs(false), method: -1, path: 0, handler: 1},
+	{pkg: "github.com/go-chi/chi", methods: map[string]string{"Handle": "", "HandleFunc": ""}, method: -1, path: 0, handler: 1},
+	{pkg: "github.com/go-chi/chi", methods: map[string]string{"Method": "", "MethodFunc": ""}, method: 0, path: 1, handler: 2},
+	{pkg: "github.com/gin-gonic/gin", methods: verbFuncs(true), method: -1, path: 0, handler: -1},
+	{pkg: "github.com/gin-gonic/gin", methods: map[string]string{"Handle": ""}, method: 0, path: 1, handler: -1},
+	{pkg: "github.com/labstack/echo", methods: verbFuncs(true), method: -1, path: 0, handler: 1},
+	{pkg: "github.com/labstack/echo", methods: map[string]string{"Add": ""}, method: 0, path: 1, handler: 2},
+}
+
+// routerGroup describes a function that returns a router for a path prefix, or passes one to a function literal
+// prefix is the argument index of the prefix, negative when the router keeps the prefix of its receiver,
+// fn is the argument index of the function literal taking the router, negative when there is none
+type routerGroup struct {
+	pkg    string
+	name   string
+	prefix int
+	fn     int
+}
+
+// GROUPS are the route groups and subrouters swagson follows, their prefix is added to the paths registered on them
+// chi also mounts routers with Mount, gorilla/mux builds subrouters with PathPrefix(prefix).Subrouter()
+var GROUPS = []routerGroup{
+	{pkg: "github.com/gin-gonic/gin", name: "Group", prefix: 0, fn: -1},
+	{pkg: "github.com/labstack/echo", name: "Group", prefix: 0, fn: -1},
+	{pkg: "github.com/go-chi/chi", name: "Route", prefix: 0, fn: 1},
+	{pkg: "github.com/go-chi/chi", name: "Group", prefix: -1, fn: 0},
+	{pkg: "github.com/go-chi/chi", name: "With", prefix: -1, fn: -1},
+	{pkg: "github.com/gorilla/mux", name: "PathPrefix", prefix: 0, fn: -1},
+	{pkg: "github.com/gorilla/mux", name: "Subrouter", prefix: -1, fn: -1},
+}
+
+// ROOTROUTERS are the router types that never carry a path prefix, as package path and type name
+var ROOTROUTERS = [][2]string{
+	{"net/http", "ServeMux"},
+	{"github.com/gin-gonic/gin", "Engine"},
+	{"github.com/labstack/echo", "Echo"},
+}
+
+// pathVariable matches the path variables of every supported router: {id}, {id:[0-9]+}, {id...}, :id and *id
+var pathVariable = regexp.MustCompile(`\{([^}:.]+)(?::[^}]*|\.\.\.)?\}|[:*]([A-Za-z_][A-Za-z0-9_]*)`)
+
+// findRoutes returns the route registrations of the given files of a loaded package
+// callees are identified through type information, so registrations in files that fail to type check may be missed.
+// The prefixes of route groups are looked up in the routers of the whole package.
+func findRoutes(pkg *packages.Package, files []*ast.File, prefixes *routerPrefixes) []route {
+	var routes []route
+	for _, f := range files {
+		// gorilla/mux declares the methods of a route with a call chained on the registration
+		var chained = make(map[*ast.CallExpr][]string)
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "Methods" {
+				return true
+			}
+			if inner, ok := sel.X.(*ast.CallExpr); ok {
+				for _, arg := range call.Args {
+					if method, ok := stringValue(pkg.TypesInfo, arg); ok {
+						chained[inner] = append(chained[inner], strings.ToUpper(method))
+					}
+				}
+			}
+			return true
+		})
+
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			routes = append(routes, registration(pkg, prefixes, call, chained[call])...)
+			return true
+		})
+	}
+	return routes
+}
+
+// registration returns the routes registered by a call, or nothing when the call is not a route registration
+func registration(pkg *packages.Package, prefixes *routerPrefixes, call *ast.CallExpr, chained []string) []route {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	callee := pkg.TypesInfo.Uses[sel.Sel]
+	if callee == nil || callee.Pkg() == nil {
+		return nil
+	}
+	for _, r := range ROUTERS {
+		method, ok := r.methods[sel.Sel.Name]
+		if !ok || !inPackage(callee.Pkg().Path(), r.pkg) {
+			continue
+		}
+		handler := r.handler
+		if handler < 0 {
+			handler += len(call.Args)
+		}
+		if len(call.Args) <= r.path || handler < 0 || handler >= len(call.Args) {
+			return nil
+		}
+		path, ok := stringValue(pkg.TypesInfo, call.Args[r.path])
+		if !ok {
+			return nil
+		}
+		fn := handlerFunc(pkg.TypesInfo, call.Args[handler])
+		if fn == nil {
+			return nil
+		}
+
+		var methods = chained
+		if method != "" {
+			methods = []string{method}
+		} else if r.method >= 0 {
+			m, ok := stringValue(pkg.TypesInfo, call.Args[r.method])
+			if !ok {
+				return nil
+			}
+			methods = []string{strings.ToUpper(m)}
+		} else if r.pkg == "net/http" {
+			// Go 1.22 patterns are [METHOD ][HOST]/PATH
+			if i := strings.Index(path, " "); i >= 0 {
+				methods = []string{strings.ToUpper(path[:i])}
+				path = strings.TrimSpace(path[i+1:])
+			}
+			if i := strings.Index(path, "/"); i > 0 {
+				path = path[i:]
+			}
+			path = strings.Replace(path, "{$}", "", -1)
+		}
+		if len(methods) == 0 {
+			methods = []string{""}
+		}
+		prefix, known := prefixes.resolve(sel.X, make(map[types.Object]bool))
+		if !known {
+			known = rootRouter(pkg.TypesInfo.TypeOf(sel.X))
+		}
+		path = joinPath(prefix, path)
+
+		var routes []route
+		for _, m := range methods {
+			routes = append(routes, route{
+				Method:      m,
+				Path:        pathVariable.ReplaceAllString(path, "{$1$2}"),
+				Handler:     fn.Name(),
+				HandlerFile: pkg.Fset.Position(fn.Pos()).Filename,
+				Pos:         pkg.Fset.Position(call.Pos()),
+				Unprefixed:  !known,
+			})
+		}
+		return routes
+	}
+	return nil
+}
+
+// routerPrefixes follows the routers of a package back to the group, subrouter or mount they come from
+// values holds the expression first assigned to every variable, params the chi Route and Group calls passing
+// their router to a function literal parameter, and mounts the chi Mount calls mounting a router variable,
+// directly or as the result of a function of the package.
+type routerPrefixes struct {
+	info   *types.Info
+	values map[types.Object]ast.Expr
+	params map[types.Object]*ast.CallExpr
+	mounts map[types.Object]*ast.CallExpr
+}
+
+func newRouterPrefixes(pkg *packages.Package) *routerPrefixes {
+	var p = &routerPrefixes{
+		info:   pkg.TypesInfo,
+		values: make(map[types.Object]ast.Expr),
+		params: make(map[types.Object]*ast.CallExpr),
+		mounts: make(map[types.Object]*ast.CallExpr),
+	}
+	var funcs = make(map[types.Object]*ast.FuncDecl)
+	var mounted = make(map[types.Object]*ast.CallExpr)
+	assign := func(lhs []ast.Expr, rhs []ast.Expr) {
+		if len(lhs) != len(rhs) {
+			return
+		}
+		for i, expr := range lhs {
+			if obj := p.object(expr); obj != nil && p.values[obj] == nil {
+				p.values[obj] = rhs[i]
+			}
+		}
+	}
+	for _, f := range pkg.Syntax {
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncDecl:
+				funcs[p.info.Defs[n.Name]] = n
+			case *ast.AssignStmt:
+				assign(n.Lhs, n.Rhs)
+			case *ast.ValueSpec:
+				var names []ast.Expr
+				for _, name := range n.Names {
+					names = append(names, name)
+				}
+				assign(names, n.Values)
+			case *ast.CallExpr:
+				if _, g := p.group(n); g != nil && g.fn >= 0 && g.fn < len(n.Args) {
+					if lit, ok := n.Args[g.fn].(*ast.FuncLit); ok && len(lit.Type.Params.List) > 0 && len(lit.Type.Params.List[0].Names) > 0 {
+						p.params[p.info.Defs[lit.Type.Params.List[0].Names[0]]] = n
+					}
+				}
+				if p.calls(n, "github.com/go-chi/chi", "Mount") && len(n.Args) == 2 {
+					switch arg := ast.Unparen(n.Args[1]).(type) {
+					case *ast.Ident:
+						p.mounts[p.info.Uses[arg]] = n
+					case *ast.CallExpr:
+						if fn, ok := arg.Fun.(*ast.Ident); ok {
+							mounted[p.info.Uses[fn]] = n
+						}
+					}
+				}
+			}
+			return true
+		})
+	}
+	// a router built and returned by a function is mounted where the function is called
+	for fn, call := range mounted {
+		decl := funcs[fn]
+		if decl == nil || decl.Body == nil {
+			continue
+		}
+		ast.Inspect(decl.Body, func(n ast.Node) bool {
+			if _, ok := n.(*ast.FuncLit); ok {
+				return false
+			}
+			if ret, ok := n.(*ast.ReturnStmt); ok && len(ret.Results) == 1 {
+				if obj := p.object(ret.Results[0]); obj != nil {
+					p.mounts[obj] = call
+				}
+			}
+			return true
+		})
+	}
+	return p
+}
+
+// object returns the variable an expression names, nil for any other expression
+func (p *routerPrefixes) object(expr ast.Expr) types.Object {
+	id, ok := ast.Unparen(expr).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	if obj := p.info.Defs[id]; obj != nil {
+		return obj
+	}
+	return p.info.Uses[id]
+}
+
+// calls reports whether call is a method or function of the router package pkg with the given name
+func (p *routerPrefixes) calls(call *ast.CallExpr, pkg string, name string) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	callee := p.info.Uses[sel.Sel]
+	return callee != nil && callee.Pkg() != nil && inPackage(callee.Pkg().Path(), pkg)
+}
+
+// group returns the receiver and description of a call returning a route group or subrouter, nil for any other call
+func (p *routerPrefixes) group(call *ast.CallExpr) (*ast.SelectorExpr, *routerGroup) {
+	for i := range GROUPS {
+		if p.calls(call, GROUPS[i].pkg, GROUPS[i].name) {
+			return call.Fun.(*ast.SelectorExpr), &GROUPS[i]
+		}
+	}
+	return nil, nil
+}
+
+// resolve returns the path prefix of the router an expression evaluates to, reporting whether it could be followed
+// routers made by a router package, like gin.Default() or chi.NewRouter(), have no prefix. Routers passed
+// as function parameters or kept in struct fields cannot be followed.
+func (p *routerPrefixes) resolve(expr ast.Expr, seen map[types.Object]bool) (string, bool) {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.CallExpr:
+		sel, g := p.group(e)
+		if g == nil {
+			fn := typeutil.Callee(p.info, e)
+			if fn == nil || fn.Pkg() == nil {
+				return "", false
+			}
+			for _, r := range ROUTERS {
+				if inPackage(fn.Pkg().Path(), r.pkg) {
+					return "", true
+				}
+			}
+			return "", false
+		}
+		prefix, known := p.resolve(sel.X, seen)
+		if g.prefix < 0 {
+			return prefix, known
+		}
+		if g.prefix >= len(e.Args) {
+			return prefix, false
+		}
+		path, ok := stringValue(p.info, e.Args[g.prefix])
+		return joinPath(prefix, path), known && ok
+	case *ast.Ident:
+		obj := p.info.Uses[e]
+		if _, ok := obj.(*types.PkgName); ok {
+			return "", true
+		}
+		if obj == nil || seen[obj] {
+			return "", false
+		}
+		seen[obj] = true
+		if call := p.mounts[obj]; call != nil {
+			prefix, known := p.resolve(call.Fun.(*ast.SelectorExpr).X, seen)
+			path, ok := stringValue(p.info, call.Args[0])
+			return joinPath(prefix, path), known && ok
+		}
+		if call := p.params[obj]; call != nil {
+			return p.resolve(call, seen)
+		}
+		if value := p.values[obj]; value != nil {
+			return p.resolve(value, seen)
+		}
+	}
+	return "", false
+}
+
+// rootRouter reports whether t is a router type that never has a path prefix
+func rootRouter(t types.Type) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	for _, root := range ROOTROUTERS {
+		if inPackage(named.Obj().Pkg().Path(), root[0]) && named.Obj().Name() == root[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// joinPath appends a path to the prefix of its router with a single slash between them
+func joinPath(prefix string, path string) string {
+	if prefix == "" {
+		return path
+	}
+	if path == "" {
+		return prefix
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// inPackage reports whether path is the router package or one of its major versions, like github.com/go-chi/chi/v5
+func inPackage(path string, pkg string) bool {
+	return path == pkg || strings.HasPrefix(path, pkg+"/v")
+}
+
+// stringValue returns the value of a constant string expression
+func stringValue(info *types.Info, expr ast.Expr) (string, bool) {
+	tv, ok := info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// handlerFunc returns the function a handler expression refers to
+// conversions such as http.HandlerFunc(getPet) are unwrapped, function literals have no name and are ignored
+func handlerFunc(info *types.Info, expr ast.Expr) *types.Func {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return handlerFunc(info, e.X)
+	case *ast.CallExpr:
+		if tv, ok := info.Types[e.Fun]; ok && tv.IsType() && len(e.Args) == 1 {
+			return handlerFunc(info, e.Args[0])
+		}
+	case *ast.Ident:
+		fn, _ := info.Uses[e].(*types.Func)
+		return fn
+	case *ast.SelectorExpr:
+		fn, _ := info.Uses[e.Sel].(*types.Func)
+		return fn
+	}
+	return nil
+}
+
+// bindRoutes ties the route registrations to the api:route comments written on their handlers
+// a comment whose keys are not paths only holds the operation, or a map of verbs to operations,
+// and gets the paths and methods of its handler's registrations. Registrations of handlers without
+// an api:route comment, registrations on routers whose prefix cannot be followed and comments on handlers
+// that are never registered are returned as warnings.
+func bindRoutes(docs []markupDoc, routes []route) []error {
+	var warnings []error
+	var documented = make(map[string]bool)
+	for i := range docs {
+		doc := &docs[i]
+		if doc.Handler == "" {
+			continue
+		}
+		documented[doc.File+"."+doc.Handler] = true
+		var body map[string]interface{}
+		if doc.decode(&body) != nil {
+			continue
+		}
+		var bound []route
+		for _, r := range routes {
+			if r.Handler == doc.Handler && r.HandlerFile == doc.File {
+				bound = append(bound, r)
+			}
+		}
+
+		if hasPathKeys(body) {
+			if len(routes) == 0 {
+				continue
+			}
+			for _, path := range sortedKeys(body) {
+				for _, verb := range sortedKeys(asMap(body[path])) {
+					if !isVerb(verb) || matchesRoute(bound, path, verb) {
+						continue
+					}
+					warnings = append(warnings, doc.errorf("%s %s of %s is not registered with a router", strings.ToUpper(verb), path, doc.Handler))
+				}
+			}
+			continue
+		}
+
+		if len(bound) == 0 {
+			warnings = append(warnings, doc.errorf("%s has no route registration, the operation is left out", doc.Handler))
+			continue
+		}
+		verbKeys := hasVerbKeys(body)
+		for _, r := range bound {
+			if r.Method == "" && !verbKeys {
+				warnings = append(warnings, doc.errorf("%s is registered for every method at %s, declare the verbs in the api:route comment", r.Path, displayPosition(r.Pos)))
+				continue
+			}
+			if r.Method != "" && !isVerb(r.Method) {
+				warnings = append(warnings, doc.errorf("%s %s is registered at %s with a method swagger 2.0 has no operation for, the operation is left out", r.Method, r.Path, displayPosition(r.Pos)))
+				continue
+			}
+			doc.Routes = append(doc.Routes, r)
+		}
+	}
+
+	for _, r := range routes {
+		if r.Unprefixed {
+			warnings = append(warnings, &markupError{Pos: r.Pos, Node: specs.APIROUTE, Msg: strings.TrimSpace(r.Method+" "+r.Path) + " is registered on a router whose path prefix cannot be followed, the path may be incomplete"})
+		}
+		if !documented[r.HandlerFile+"."+r.Handler] {
+			warnings = append(warnings, &markupError{Pos: r.Pos, Node: specs.APIROUTE, Msg: strings.TrimSpace(r.Method+" "+r.Path) + " is registered without api:route docs"})
+		}
+	}
+	return warnings
+}
+
+// routePaths decodes an api:route comment into the paths it declares
+// comments bound to route registrations are expanded into a path item per registration
+func routePaths(doc markupDoc) (specs.SwagPaths, error) {
+	var paths specs.SwagPaths
+	var body map[string]interface{}
+	if len(doc.Routes) == 0 {
+		// comments on unregistered handlers that only hold an operation were reported by bindRoutes
+		if doc.Handler != "" && doc.decode(&body) == nil && !hasPathKeys(body) {
+			return paths, nil
+		}
+		err := doc.decode(&paths)
+		return paths, err
+	}
+
+	if err := doc.decode(&body); err != nil {
+		return paths, err
+	}
+	var ops map[string]*specs.SwagOperation
+	if hasVerbKeys(body) {
+		if err := doc.decode(&ops); err != nil {
+			return paths, err
+		}
+	} else {
+		var op specs.SwagOperation
+		if err := doc.decode(&op); err != nil {
+			return paths, err
+		}
+		ops = make(map[string]*specs.SwagOperation)
+		for _, r := range doc.Routes {
+			copied := op
+			ops[strings.ToLower(r.Method)] = &copied
+		}
+	}
+
+	paths.Paths = make(map[string]specs.SwagPath)
+	for _, r := range doc.Routes {
+		path := paths.Paths[r.Path]
+		for _, verb := range sortedKeys(ops) {
+			op := ops[verb]
+			if r.Method != "" && !strings.EqualFold(r.Method, verb) {
+				continue
+			}
+			var declared specs.SwagPath
+			field := verbOperation(&declared, strings.ToLower(verb))
+			if field == nil {
+				continue
+			}
+			*field = op
+			mergePath(&path, &declared)
+		}
+		paths.Paths[r.Path] = path
+	}
+	return paths, nil
+}
+
+// verbOperation returns the operation field of a path item for the given lower case verb
+func verbOperation(path *specs.SwagPath, verb string) **specs.SwagOperation {
+	switch verb {
+	case "get":
+		return &path.Get
+	case "put":
+		return &path.Put
+	case "post":
+		return &path.Post
+	case "delete":
+		return &path.Delete
+	case "options":
+		return &path.Options
+	case "head":
+		return &path.Head
+	case "patch":
+		return &path.Patch
+	}
+	return nil
+}
+
+// matchesRoute reports whether one of the routes registers the path and verb
+func matchesRoute(routes []route, path string, verb string) bool {
+	for _, r := range routes {
+		if r.Path == path && (r.Method == "" || strings.EqualFold(r.Method, verb)) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the keys of a map in sorted order, so that walking it does not depend on map iteration
+func sortedKeys[V any](m map[string]V) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func hasPathKeys(body map[string]interface{}) bool {
+	for k := range body {
+		if strings.HasPrefix(k, "/") {
+			return true
+		}
+	}
+	return false
+}
+
+func hasVerbKeys(body map[string]interface{}) bool {
+	for k := range body {
+		if !isVerb(k) {
+			return false
+		}
+	}
+	return len(body) > 0
+}
+
+func isVerb(key string) bool {
+	for _, verb := range VERBS {
+		if strings.EqualFold(key, verb) {
+			return true
+		}
+	}
+	return false
+}
+
+func asMap(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
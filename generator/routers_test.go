@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/sfodje/swagson/specs"
+)
+
+func Test_findRoutes(t *testing.T) {
+	pkgs, err := loadPackages(context.Background(), "./testdata/routers/", "", nil, &Diagnostics{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found []string
+	for _, r := range findRoutes(pkgs[0], pkgs[0].Syntax, newRouterPrefixes(pkgs[0])) {
+		found = append(found, strings.TrimSpace(r.Method+" "+r.Path)+" "+r.Handler)
+	}
+	sort.Strings(found)
+	expected := []string{
+		"/health health",
+		"DELETE /user/{username} deleteUser",
+		"GET /api/pets listPets",
+		"GET /pet/{petId} getPet",
+		"GET /store/inventory getInventory",
+		"GET /store/order/{orderId} getOrder",
+		"GET /user/{username} getUser",
+		"GET /users listUsers",
+		"GET /v1/pets/{petId} findPet",
+		"GET /v2/pets/{petId} findPetV2",
+		"HEAD /store/order/{orderId} getOrder",
+		"POST /admin/reindex reindex",
+		"POST /pet addPet",
+		"PUT /pet/{petId} updatePet",
+		"TRACE /pet tracePet",
+	}
+	if strings.Join(found, "\n") == strings.Join(expected, "\n") {
+		t.Log("findRoutes(net/http, gorilla/mux, chi, gin, echo) passed.")
+	} else {
+		t.Log(found)
+		t.Error("findRoutes(net/http, gorilla/mux, chi, gin, echo) failed.")
+	}
+}
+
+func Test_bindRoutes(t *testing.T) {
+	swagDoc, diags := Build(context.Background(), Options{Dir: "./testdata/routers/", SkipValidation: true})
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	paths := swagDoc.Paths.Paths
+	if paths["/pet/{petId}"].Get.Summary == "Find pet by ID" && paths["/store/order/{orderId}"].Head != nil &&
+		paths["/health"].Get != nil && paths["/pet"].Post != nil && paths["/user/{username}"].Delete.Summary == "Delete user" &&
+		paths["/v1/pets/{petId}"].Get != nil && paths["/admin/reindex"].Post != nil && paths["/api/pets"].Get != nil {
+		t.Log("Build(routers) paths passed.")
+	} else {
+		t.Error("Build(routers) paths failed.")
+	}
+
+	params := *paths["/store/order/{orderId}"].Get.Parameters
+	if len(params) == 1 && params[0].Name == "orderId" && params[0].In == specs.PATH.String() {
+		t.Log("Build(routers) path parameters passed.")
+	} else {
+		t.Log(params)
+		t.Error("Build(routers) path parameters failed.")
+	}
+
+	var warnings []string
+	for _, d := range diags {
+		if d.Node == specs.APIROUTE.String() && !strings.HasPrefix(d.Message, "path parameter") {
+			warnings = append(warnings, d.Message)
+		}
+	}
+	sort.Strings(warnings)
+	expected := []string{
+		"GET /users is registered on a router whose path prefix cannot be followed, the path may be incomplete",
+		"PUT /pet/{petId} is registered without api:route docs",
+		"TRACE /pet is registered at testdata/routers/main.go:20:2 with a method swagger 2.0 has no operation for, the operation is left out",
+		"unused has no route registration, the operation is left out",
+	}
+	if strings.Join(warnings, "\n") == strings.Join(expected, "\n") {
+		t.Log("Build(routers) warnings passed.")
+	} else {
+		t.Log(warnings)
+		t.Error("Build(routers) warnings failed.")
+	}
+}
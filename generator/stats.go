@@ -0,0 +1,50 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Stats holds the counts and timings of a run
+type Stats struct {
+	Packages int
+	Files    int
+	Comments int
+	// Cached is the number of files whose markup was read from the cache instead of parsing the file
+	Cached  int
+	Workers int
+	// Load is the time spent loading, parsing and type checking the packages
+	Load time.Duration
+	// Extract is the time spent extracting markup, route registrations and model types from the files
+	Extract time.Duration
+	// Assemble is the time spent building the document from the extracted markup and checking it
+	Assemble time.Duration
+	// Validate is the time spent validating the document against the swagger 2.0 schema
+	Validate time.Duration
+}
+
+// Total returns the time spent on the whole run
+func (s Stats) Total() time.Duration {
+	return s.Load + s.Extract + s.Assemble + s.Validate
+}
+
+// String formats the statistics as one line per step of the run
+func (s Stats) String() string {
+	lines := []string{
+		fmt.Sprintf("loaded %d packages in %s", s.Packages, duration(s.Load)),
+		fmt.Sprintf("extracted %d markup comments from %d files (%d cached) in %s with %d workers", s.Comments, s.Files, s.Cached, duration(s.Extract), s.Workers),
+		fmt.Sprintf("assembled the document in %s", duration(s.Assemble)),
+		fmt.Sprintf("validated the document in %s", duration(s.Validate)),
+		fmt.Sprintf("total %s", duration(s.Total())),
+	}
+	return strings.Join(lines, "\n")
+}
+
+// duration rounds a duration for display
+func duration(d time.Duration) string {
+	if d >= time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(time.Microsecond).String()
+}
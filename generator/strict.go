@@ -0,0 +1,307 @@
+package generator
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/sfodje/swagson/specs"
+)
+
+// markupTypes is the specs type the body of each markup node is decoded into
+var markupTypes = map[specs.MarkupNode]reflect.Type{
+	specs.APIMETA:               reflect.TypeOf(specs.SwagDoc{}),
+	specs.APIROUTE:              reflect.TypeOf(specs.SwagPaths{}),
+	specs.APIMODEL:              reflect.TypeOf(map[string]specs.SwagSchema{}),
+	specs.APIPARAMETER:          reflect.TypeOf(map[string]specs.SwagParam{}),
+	specs.APIRESPONSE:           reflect.TypeOf(map[string]specs.SwagResponse{}),
+	specs.APISECURITYDEFINITION: reflect.TypeOf(map[string]specs.SwagSecDef{}),
+}
+
+var schemaOrBool = reflect.TypeOf(specs.SwagSchemaOrBool{})
+
+// extensibleMaps are the specs objects holding a map of specs objects beside their vendor extensions, along with the type of the map
+var extensibleMaps = map[reflect.Type]reflect.Type{
+	reflect.TypeOf(specs.SwagPaths{}):     reflect.TypeOf(map[string]specs.SwagPath{}),
+	reflect.TypeOf(specs.SwagResponses{}): reflect.TypeOf(map[string]specs.SwagResponse{}),
+}
+
+// Markup keys follow one policy: a key names a field of a specs object when it is written
+// exactly like the field in the swagger spec, like operationId or $ref, or like that name with
+// its first letter capitalized, like OperationId. Vendor extensions are kept as written.
+// Any other key, including other spellings of a field name like operationid, matches no field.
+
+// checkKeys reports the keys of a markup body that match no field of the specs type it is decoded into
+// these are left out of the document, so a misspelled key would quietly leave data out without the report.
+// Each key is reported at its line in the comment with the closest known field name as a suggestion.
+func checkKeys(doc markupDoc) error {
+	t := markupType(doc)
+	if t == nil {
+		return nil
+	}
+	// bodies that cannot be decoded are reported while the document is built
+	_, errs, err := doc.canonical(t)
+	if err != nil {
+		return nil
+	}
+	return errs.err()
+}
+
+// canonical decodes the markup body into ordered objects, renaming every key that matches a field of t
+// to the json name of the field and leaving out the keys that match none, which are returned as errors
+func (m markupDoc) canonical(t reflect.Type) (interface{}, errorList, error) {
+	j, err := m.json()
+	if err != nil {
+		return nil, nil, err
+	}
+	body, err := decodeOrdered(j)
+	if err != nil {
+		return nil, nil, m.wrap(err)
+	}
+	var c = keyChecker{doc: m, lines: strings.Split(m.Text, "\n")}
+	body = c.check(body, t, "")
+	return body, c.errs, nil
+}
+
+// markupType returns the type the body of doc is decoded into, or nil when the body is not decoded
+// route comments on registered handlers hold a single operation or one operation per verb
+func markupType(doc markupDoc) reflect.Type {
+	if doc.Node != specs.APIROUTE || doc.Handler == "" {
+		return markupTypes[doc.Node]
+	}
+	var body map[string]interface{}
+	if doc.decode(&body) != nil {
+		return nil
+	}
+	if len(doc.Routes) == 0 {
+		if hasPathKeys(body) {
+			return markupTypes[doc.Node]
+		}
+		return nil
+	}
+	if hasVerbKeys(body) {
+		return reflect.TypeOf(map[string]specs.SwagOperation{})
+	}
+	return reflect.TypeOf(specs.SwagOperation{})
+}
+
+// keyChecker walks a decoded markup body along with its specs type
+// keys are visited in the order they are written, so their lines are looked up moving forward through the body
+type keyChecker struct {
+	doc   markupDoc
+	lines []string
+	next  int
+	errs  errorList
+}
+
+// check returns v with the keys of its objects following the key policy
+func (c *keyChecker) check(v interface{}, t reflect.Type, path string) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == schemaOrBool {
+		t = reflect.TypeOf(specs.SwagSchema{})
+	}
+	var extensible bool
+	if m, ok := extensibleMaps[t]; ok {
+		t, extensible = m, true
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		o, ok := v.(*ordered)
+		if !ok {
+			return v
+		}
+		var canonical = newOrdered()
+		var written = make(map[string]string)
+		fields := jsonFields(t)
+		for _, key := range o.keys {
+			line := c.line(key)
+			name := key
+			field, ok := matchField(fields, key)
+			if ok {
+				name = jsonName(field)
+			} else if !isExtension(key) {
+				c.errs.add(c.unknown(line, key, keyPath(path, key), fields))
+				continue
+			}
+			if previous, ok := written[name]; ok {
+				c.errs.add(c.doc.errorAt(line, "key %s is already set as %s", keyPath(path, key), previous))
+				continue
+			}
+			written[name] = key
+			if ok {
+				canonical.set(name, c.check(o.values[key], field.Type, keyPath(path, key)))
+			} else {
+				canonical.set(name, o.values[key])
+			}
+		}
+		return canonical
+	case reflect.Map:
+		o, ok := v.(*ordered)
+		if !ok {
+			return v
+		}
+		var canonical = newOrdered()
+		for _, key := range o.keys {
+			c.line(key)
+			if extensible && isExtension(key) {
+				canonical.set(key, o.values[key])
+				continue
+			}
+			canonical.set(key, c.check(o.values[key], t.Elem(), keyPath(path, key)))
+		}
+		return canonical
+	case reflect.Slice, reflect.Array:
+		list, ok := v.([]interface{})
+		if !ok {
+			return v
+		}
+		var canonical = make([]interface{}, len(list))
+		for i, item := range list {
+			canonical[i] = c.check(item, t.Elem(), path)
+		}
+		return canonical
+	}
+	return v
+}
+
+// line returns the line of the body, counting from zero, holding the next occurrence of key
+// keys that cannot be found, like those of flow mappings, are reported at the line of the previous key
+func (c *keyChecker) line(key string) int {
+	pattern := regexp.MustCompile(`^\s*(?:-\s+)*["']?` + regexp.QuoteMeta(key) + `["']?\s*:`)
+	for i := c.next; i < len(c.lines); i++ {
+		if pattern.MatchString(c.lines[i]) {
+			c.next = i + 1
+			return i
+		}
+	}
+	if c.next > 0 {
+		return c.next - 1
+	}
+	return 0
+}
+
+// unknown returns the error of an unknown key, suggesting the closest known field name
+func (c *keyChecker) unknown(line int, key string, path string, fields []reflect.StructField) error {
+	for _, f := range fields {
+		if name := jsonName(f); strings.EqualFold(name, key) {
+			return c.doc.errorAt(line, "key %s is not spelled like the spec, write it %s or %s", path, name, capitalize(name))
+		}
+	}
+	if suggestion := suggestKey(key, fields); suggestion != "" {
+		return c.doc.errorAt(line, "unknown key %s, did you mean %s?", path, suggestion)
+	}
+	return c.doc.errorAt(line, "unknown key %s", path)
+}
+
+// keyPath appends key to the dotted path of its parent
+func keyPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// jsonFields returns the fields encoding/json decodes into, including those of embedded structs
+func jsonFields(t reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			fields = append(fields, jsonFields(f.Type)...)
+			continue
+		}
+		if jsonName(f) != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// jsonName returns the name of a field in json, or an empty string when encoding/json skips the field
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	name = strings.TrimSpace(name)
+	if name == "-" || f.PkgPath != "" {
+		return ""
+	}
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// matchField returns the field a key decodes into, the key is either the json name of the field or that name capitalized
+func matchField(fields []reflect.StructField, key string) (reflect.StructField, bool) {
+	for _, f := range fields {
+		if name := jsonName(f); key == name || key == capitalize(name) {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// capitalize returns name with its first letter in upper case
+func capitalize(name string) string {
+	r := []rune(name)
+	if len(r) == 0 {
+		return name
+	}
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// suggestKey returns the known field name closest to key, written in the same style as key
+// names further away than a third of their length are not suggested
+func suggestKey(key string, fields []reflect.StructField) string {
+	var best string
+	var bestDistance = len(key)/3 + 1
+	for _, f := range fields {
+		name := jsonName(f)
+		if d := editDistance(strings.ToLower(key), strings.ToLower(name)); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	// the examples write keys capitalized, suggest the name the way the key was written
+	if r := []rune(key); unicode.IsUpper(r[0]) {
+		return capitalize(best)
+	}
+	return best
+}
+
+// editDistance returns the number of single character edits turning a into b
+// swapping two adjacent characters, a common typo, counts as a single edit
+func editDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+// isExtension reports whether a key is a vendor extension, these are kept on every spec object
+func isExtension(key string) bool {
+	return strings.HasPrefix(strings.ToLower(key), "x-")
+}
@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/sfodje/swagson/specs"
+)
+
+func Test_checkKeys(t *testing.T) {
+	doc := markupDoc{Node: specs.APIROUTE, File: "pet.go", Line: 10, Column: 1, Text: `/pet/{petId}:
+    x-internal: true
+    Get:
+        OperationId: getPetById
+        Parameters:
+            - Name: petId
+              In: path
+              Requred: true
+        Respones:
+            200:
+                Description: ok`}
+	err := checkKeys(doc)
+	expected := "pet.go:17:1: api:route: unknown key /pet/{petId}.Get.Parameters.Requred, did you mean Required?\n" +
+		"pet.go:18:1: api:route: unknown key /pet/{petId}.Get.Respones, did you mean Responses?"
+	if err != nil && err.Error() == expected {
+		t.Log("checkKeys(misspelled route keys) passed.")
+	} else {
+		t.Log(err)
+		t.Error("checkKeys(misspelled route keys) failed.")
+	}
+
+	doc = markupDoc{Node: specs.APIMODEL, File: "pet.go", Line: 2, Column: 1, Text: "Pet:\n    type: object\n    propertes:\n        id:\n            type: integer\n    color: blue"}
+	err = checkKeys(doc)
+	expected = "pet.go:4:1: api:model: unknown key Pet.propertes, did you mean properties?\n" +
+		"pet.go:7:1: api:model: unknown key Pet.color"
+	if err != nil && err.Error() == expected {
+		t.Log("checkKeys(misspelled model keys) passed.")
+	} else {
+		t.Log(err)
+		t.Error("checkKeys(misspelled model keys) failed.")
+	}
+
+	doc = markupDoc{Node: specs.APIROUTE, File: "pet.go", Line: 2, Column: 1, Handler: "getPet", Routes: []route{{Method: "GET", Path: "/pet"}}, Text: "Summary: Find pets\nTgas: [pet]"}
+	err = checkKeys(doc)
+	if err != nil && err.Error() == "pet.go:3:1: api:route: unknown key Tgas, did you mean Tags?" {
+		t.Log("checkKeys(handler operation) passed.")
+	} else {
+		t.Log(err)
+		t.Error("checkKeys(handler operation) failed.")
+	}
+
+	doc = markupDoc{Node: specs.APIMETA, File: "meta.go", Line: 2, Column: 1, Text: "Swagger: \"2.0\"\nInfo:\n    Title: pets\n    Contact:\n        Email: a@b.c\n        Name: pets"}
+	if err := checkKeys(doc); err == nil {
+		t.Log("checkKeys(known keys) passed.")
+	} else {
+		t.Log(err)
+		t.Error("checkKeys(known keys) failed.")
+	}
+}
+
+func Test_keyPolicy(t *testing.T) {
+	doc := markupDoc{Node: specs.APIMODEL, File: "pet.go", Line: 2, Column: 1, Text: `Pet:
+    type: object
+    Properties:
+        name:
+            type: string
+            maxLength: 10
+            MinLength: 1
+            maxlength: 20
+        tags:
+            $ref: "#/definitions/Tags"
+            Type: array
+            type: object`}
+	err := checkKeys(doc)
+	expected := "pet.go:9:1: api:model: key Pet.Properties.name.maxlength is not spelled like the spec, write it maxLength or MaxLength\n" +
+		"pet.go:13:1: api:model: key Pet.Properties.tags.type is already set as Type"
+	if err != nil && err.Error() == expected {
+		t.Log("checkKeys(key policy) passed.")
+	} else {
+		t.Log(err)
+		t.Error("checkKeys(key policy) failed.")
+	}
+
+	var models map[string]specs.SwagSchema
+	if err := doc.decode(&models); err != nil {
+		t.Fatal(err)
+	}
+	j, err := DocToJson(models)
+	expected = `{"Pet":{"type":"object","properties":{"name":{"maxLength":10,"minLength":1,"type":"string"},"tags":{"$ref":"#/definitions/Tags","type":"array"}}}}`
+	if err == nil && string(*j) == expected {
+		t.Log("decode(models, key policy) passed.")
+	} else {
+		t.Log(err, string(*j))
+		t.Error("decode(models, key policy) failed.")
+	}
+
+	var contact specs.SwagContact
+	doc = markupDoc{Node: specs.APIMETA, Text: "name: pets\nEmail: apiteam@swagger.io"}
+	if err := doc.decode(&contact); err != nil {
+		t.Fatal(err)
+	}
+	j, err = DocToJson(contact)
+	if err == nil && string(*j) == `{"name":"pets","email":"apiteam@swagger.io"}` {
+		t.Log("decode(contact, key policy) passed.")
+	} else {
+		t.Log(err, string(*j))
+		t.Error("decode(contact, key policy) failed.")
+	}
+}
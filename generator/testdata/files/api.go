@@ -0,0 +1,9 @@
+package files
+
+/* api:meta
+Swagger: "2.0"
+Info:
+    Title: files
+    Version: "1.0.0"
+Paths: {}
+*/
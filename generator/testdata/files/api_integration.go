@@ -0,0 +1,8 @@
+//go:build integration
+
+package files
+
+/* api:model
+Integration:
+    Type: object
+*/
@@ -0,0 +1,6 @@
+package files
+
+/* api:model
+Test:
+    Type: object
+*/
@@ -0,0 +1,6 @@
+package internal
+
+/* api:model
+Store:
+    Type: object
+*/
@@ -0,0 +1,7 @@
+package a
+
+// Error api:model
+// An error of the pets api
+type Error struct {
+	Code int `json:"code"`
+}
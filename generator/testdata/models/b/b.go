@@ -0,0 +1,7 @@
+package b
+
+// Error api:model
+// An error of the store api
+type Error struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,17 @@
+module example.com/routers
+
+go 1.22
+
+require (
+	github.com/gin-gonic/gin v1.9.1
+	github.com/go-chi/chi/v5 v5.0.12
+	github.com/gorilla/mux v1.8.1
+	github.com/labstack/echo/v4 v4.11.4
+)
+
+replace (
+	github.com/gin-gonic/gin => ./stubs/gin
+	github.com/go-chi/chi/v5 => ./stubs/chi
+	github.com/gorilla/mux => ./stubs/mux
+	github.com/labstack/echo/v4 => ./stubs/echo
+)
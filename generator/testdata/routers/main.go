@@ -0,0 +1,147 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/mux"
+	"github.com/labstack/echo/v4"
+)
+
+// api:meta
+// Info:
+//
+//	Title: Routers
+//	Version: 1.0.0
+func main() {
+	http.HandleFunc("GET /pet/{petId}", getPet)
+	http.Handle("/health", http.HandlerFunc(health))
+	http.HandleFunc("TRACE /pet", tracePet)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/store/order/{orderId:[0-9]+}", getOrder).Methods("GET", "HEAD")
+	api := r.PathPrefix("/api").Subrouter()
+	api.HandleFunc("/pets", listPets).Methods("GET")
+
+	c := chi.NewRouter()
+	c.Post("/pet", addPet)
+	c.Method("PUT", "/pet/{petId}", http.HandlerFunc(updatePet))
+	c.Route("/store", func(r chi.Router) {
+		r.Get("/inventory", getInventory)
+	})
+	c.Mount("/admin", adminRouter())
+
+	g := gin.Default()
+	g.GET("/user/:username", auth, getUser)
+	v1 := g.Group("/v1")
+	v1.Group("/pets").GET("/:petId", findPet)
+	users(v1)
+
+	e := echo.New()
+	e.DELETE("/user/:username", deleteUser)
+	e.Group("/v2").GET("/pets/:petId", findPetV2)
+}
+
+func adminRouter() *chi.Mux {
+	a := chi.NewRouter()
+	a.Post("/reindex", reindex)
+	return a
+}
+
+func users(group *gin.RouterGroup) {
+	group.GET("/users", listUsers)
+}
+
+// api:route
+// Summary: Find pet by ID
+// Responses:
+//
+//	200:
+//	    Description: successful operation
+func getPet(w http.ResponseWriter, r *http.Request) {}
+
+// api:route
+// Get:
+//
+//	Summary: Health check
+//	Responses:
+//	    200:
+//	        Description: ok
+func health(w http.ResponseWriter, r *http.Request) {}
+
+// api:route
+// Summary: Find purchase order by ID
+// Responses:
+//
+//	200:
+//	    Description: successful operation
+func getOrder(w http.ResponseWriter, r *http.Request) {}
+
+// api:route
+// "/pet":
+//
+//	Post:
+//	    Summary: Add a new pet to the store
+//	    Responses:
+//	        200:
+//	            Description: successful operation
+func addPet(w http.ResponseWriter, r *http.Request) {}
+
+func updatePet(w http.ResponseWriter, r *http.Request) {}
+
+func auth(c *gin.Context) {}
+
+// api:route
+// Summary: Get user by user name
+// Responses:
+//
+//	200:
+//	    Description: successful operation
+func getUser(c *gin.Context) {}
+
+// api:route
+// Summary: Delete user
+// Responses:
+//
+//	200:
+//	    Description: successful operation
+func deleteUser(c echo.Context) error { return nil }
+
+// api:route
+// Summary: Never registered
+// Responses:
+//
+//	200:
+//	    Description: successful operation
+func unused(w http.ResponseWriter, r *http.Request) {}
+
+// api:route
+// Get:
+//
+//	Summary: List pets
+func listPets(w http.ResponseWriter, r *http.Request) {}
+
+// api:route
+// Summary: Returns pet inventories
+func getInventory(w http.ResponseWriter, r *http.Request) {}
+
+// api:route
+// Summary: Rebuild the search index
+func reindex(w http.ResponseWriter, r *http.Request) {}
+
+// api:route
+// Summary: Find pet by ID
+func findPet(c *gin.Context) {}
+
+// api:route
+// Summary: Find pet by ID
+func findPetV2(c echo.Context) error { return nil }
+
+// api:route
+// Summary: List users
+func listUsers(c *gin.Context) {}
+
+// api:route
+// Summary: Trace the pet requests
+func tracePet(w http.ResponseWriter, r *http.Request) {}
@@ -0,0 +1,24 @@
+// Package chi is the subset of the go-chi/chi API used by the router tests.
+package chi
+
+import "net/http"
+
+type Router interface {
+	http.Handler
+	Get(pattern string, h http.HandlerFunc)
+	Post(pattern string, h http.HandlerFunc)
+	Method(method, pattern string, h http.Handler)
+	Route(pattern string, fn func(r Router)) Router
+	Mount(pattern string, h http.Handler)
+}
+
+type Mux struct{}
+
+func NewRouter() *Mux { return &Mux{} }
+
+func (mx *Mux) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+func (mx *Mux) Get(pattern string, h http.HandlerFunc)           {}
+func (mx *Mux) Post(pattern string, h http.HandlerFunc)          {}
+func (mx *Mux) Method(method, pattern string, h http.Handler)    {}
+func (mx *Mux) Route(pattern string, fn func(r Router)) Router   { return mx }
+func (mx *Mux) Mount(pattern string, h http.Handler)             {}
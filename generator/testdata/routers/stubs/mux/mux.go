@@ -0,0 +1,20 @@
+// Package mux is the subset of the gorilla/mux API used by the router tests.
+package mux
+
+import "net/http"
+
+type Router struct{}
+
+type Route struct{}
+
+func NewRouter() *Router { return &Router{} }
+
+func (r *Router) HandleFunc(path string, f func(http.ResponseWriter, *http.Request)) *Route {
+	return &Route{}
+}
+
+func (r *Router) PathPrefix(tpl string) *Route { return &Route{} }
+
+func (r *Route) Methods(methods ...string) *Route { return r }
+
+func (r *Route) Subrouter() *Router { return &Router{} }
@@ -0,0 +1,65 @@
+package generator
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+
+	"github.com/sfodje/swagson/specs"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// swaggerSchema is the official JSON Schema of the Swagger 2.0 specification
+//
+//go:embed schemas/swagger-2.0.json
+var swaggerSchema []byte
+
+// validationError is a single violation of the Swagger 2.0 JSON Schema
+// Pointer is the JSON pointer of the offending value in the generated document
+type validationError struct {
+	Pointer string
+	Message string
+}
+
+func (e validationError) Error() string {
+	if e.Pointer == "" {
+		return "(root): " + e.Message
+	}
+	return e.Pointer + ": " + e.Message
+}
+
+// validateSwagDoc checks the document against the Swagger 2.0 JSON Schema and returns every violation
+// the returned error is only set when the document or the schema cannot be processed
+func validateSwagDoc(swagDoc *specs.SwagDoc) ([]validationError, error) {
+	j, err := json.Marshal(swagDoc)
+	if err != nil {
+		return nil, err
+	}
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(swaggerSchema), gojsonschema.NewBytesLoader(j))
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []validationError
+	for _, e := range result.Errors() {
+		violations = append(violations, validationError{
+			Pointer: jsonPointer(e.Context()),
+			Message: e.Description(),
+		})
+	}
+	return violations, nil
+}
+
+// jsonPointer converts a validation context into an RFC 6901 JSON pointer
+// the context only exposes its path as a joined string, so it is joined on a byte
+// that cannot appear in the document keys and split back into escaped segments
+func jsonPointer(ctx *gojsonschema.JsonContext) string {
+	segments := strings.Split(ctx.String("\x00"), "\x00")[1:]
+	var pointer string
+	for _, segment := range segments {
+		segment = strings.Replace(segment, "~", "~0", -1)
+		segment = strings.Replace(segment, "/", "~1", -1)
+		pointer += "/" + segment
+	}
+	return pointer
+}
@@ -0,0 +1,47 @@
+package generator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sfodje/swagson/specs"
+)
+
+func Test_validateSwagDoc(t *testing.T) {
+	swagDoc, diags := Build(context.Background(), Options{Dir: "../examples/", SkipValidation: true})
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	if violations, err := validateSwagDoc(swagDoc); err == nil && len(violations) == 0 {
+		t.Log("validateSwagDoc(examples) passed.")
+	} else {
+		t.Log(err, violations)
+		t.Error("validateSwagDoc(examples) failed.")
+	}
+
+	swagDoc = getSwagDoc()
+	swagDoc.Info = nil
+	swagDoc.Paths = &specs.SwagPaths{Paths: map[string]specs.SwagPath{
+		"/pet/{petId}": specs.SwagPath{
+			Get: &specs.SwagOperation{
+				Responses: &specs.SwagResponses{Responses: map[string]specs.SwagResponse{
+					"ok": specs.SwagResponse{Description: "successful operation"},
+				}},
+			},
+		},
+	}}
+	violations, err := validateSwagDoc(swagDoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pointers = make(map[string]bool)
+	for _, v := range violations {
+		pointers[v.Pointer] = true
+	}
+	if pointers["/info"] && pointers["/paths/~1pet~1{petId}/get/responses"] {
+		t.Log("validateSwagDoc(invalid) passed.")
+	} else {
+		t.Log(violations)
+		t.Error("validateSwagDoc(invalid) failed.")
+	}
+}
@@ -0,0 +1,103 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sfodje/swagson/specs"
+)
+
+// WATCHDELAY is how long Watch waits for more changes after a file changed before building the document again
+const WATCHDELAY = 300 * time.Millisecond
+
+// Watch builds the document of the project in opts.Dir, then builds it again whenever a selected go file,
+// go.mod or go.sum changes, until ctx is done. Changes are debounced: a build starts once no file changed for
+// WATCHDELAY, so saving many files at once builds the document once. Every build is handed to build along with
+// its diagnostics, problems in the project do not stop the watch. The returned error is set when the files
+// cannot be watched.
+func Watch(ctx context.Context, opts Options, build func(*specs.SwagDoc, Diagnostics)) error {
+	dir, err := filepath.Abs(opts.Dir)
+	if err != nil {
+		return err
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	if err := watchDirs(watcher, dir, dir); err != nil {
+		return err
+	}
+
+	build(Build(ctx, opts))
+	var pending <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			// fsnotify does not watch directories recursively, new directories are added as they appear
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := watchDirs(watcher, dir, event.Name); err != nil {
+						return err
+					}
+					pending = time.After(WATCHDELAY)
+				}
+			}
+			if event.Op != fsnotify.Chmod && opts.watched(dir, event.Name) {
+				pending = time.After(WATCHDELAY)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			// events were dropped, one of them may have been a change
+			if !errors.Is(err, fsnotify.ErrEventOverflow) {
+				return err
+			}
+			pending = time.After(WATCHDELAY)
+		case <-pending:
+			pending = nil
+			build(Build(ctx, opts))
+		}
+	}
+}
+
+// watchDirs watches root and every directory below it, leaving out the directories no file is selected from
+func watchDirs(watcher *fsnotify.Watcher, dir string, root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != dir && ignoredDir(d.Name()) {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
+
+// watched reports whether a change to the file at name may change the document
+func (opts Options) watched(dir string, name string) bool {
+	switch filepath.Base(name) {
+	case "go.mod", "go.sum":
+		return true
+	}
+	if !strings.HasSuffix(name, ".go") {
+		return false
+	}
+	selected, err := opts.selectFile(dir, name)
+	return err != nil || selected
+}
@@ -0,0 +1,105 @@
+package generator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sfodje/swagson/specs"
+)
+
+func Test_Options_watched(t *testing.T) {
+	opts := Options{Dir: "/project", Exclude: []string{"internal/**"}}
+	var tests = []struct {
+		name     string
+		expected bool
+	}{
+		{"/project/api.go", true},
+		{"/project/go.mod", true},
+		{"/project/api_test.go", false},
+		{"/project/README.md", false},
+		{"/project/internal/store.go", false},
+		{"/project/vendor/router/router.go", false},
+	}
+	for _, test := range tests {
+		if opts.watched("/project", test.name) == test.expected {
+			t.Logf("Options.watched(%s) passed.", test.name)
+		} else {
+			t.Errorf("Options.watched(%s) failed.", test.name)
+		}
+	}
+}
+
+func Test_Watch(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, title string) {
+		src := "package api\n\n/* api:meta\nSwagger: \"2.0\"\nInfo:\n    Title: " + title + "\n    Version: \"1.0.0\"\nPaths: {}\n*/\n"
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/api\n\ngo 1.22\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	write("api.go", "pets")
+
+	type build struct {
+		swagDoc *specs.SwagDoc
+		diags   Diagnostics
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	var builds = make(chan build, 10)
+	var done = make(chan error)
+	go func() {
+		done <- Watch(ctx, Options{Dir: dir}, func(swagDoc *specs.SwagDoc, diags Diagnostics) {
+			builds <- build{swagDoc, diags}
+		})
+	}()
+	next := func() (Diagnostics, string) {
+		select {
+		case b := <-builds:
+			var title string
+			if b.swagDoc != nil && b.swagDoc.Info != nil {
+				title = b.swagDoc.Info.Title
+			}
+			return b.diags, title
+		case <-time.After(10 * time.Second):
+			t.Fatal("Watch did not build the document.")
+		}
+		return nil, ""
+	}
+
+	if diags, title := next(); !diags.HasErrors() && title == "pets" {
+		t.Log("Watch(first build) passed.")
+	} else {
+		t.Log(diags)
+		t.Error("Watch(first build) failed.")
+	}
+
+	write("api.go", "stores")
+	write("api.go", "orders")
+	if diags, title := next(); !diags.HasErrors() && title == "orders" {
+		t.Log("Watch(debounced saves) passed.")
+	} else {
+		t.Log(diags, title)
+		t.Error("Watch(debounced saves) failed.")
+	}
+
+	write("meta.go", "pets")
+	if diags, _ := next(); diags.HasErrors() {
+		t.Log("Watch(errors) passed.")
+	} else {
+		t.Error("Watch(errors) failed.")
+	}
+
+	time.Sleep(2 * WATCHDELAY)
+	cancel()
+	if err := <-done; err == nil && len(builds) == 0 {
+		t.Log("Watch(canceled) passed.")
+	} else {
+		t.Log(err, len(builds))
+		t.Error("Watch(canceled) failed.")
+	}
+}
@@ -0,0 +1,269 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ordered is a json object that keeps its keys in the order they were declared
+// markup is converted through it so vendor extensions and merged api:meta fragments keep the author's order
+type ordered struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newOrdered() *ordered {
+	return &ordered{values: make(map[string]interface{})}
+}
+
+// set stores the value of a key, appending the key when it is new
+func (o *ordered) set(key string, value interface{}) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+func (o *ordered) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// yamlToJSON converts a yaml document to json, keeping the order of the mapping keys
+// mapping keys that are not strings, like response codes, are converted to strings as they are written
+func yamlToJSON(y []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(y, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind == 0 {
+		return []byte("null"), nil
+	}
+	v, err := fromYAML(&doc)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// duplicateKeyError is a mapping key set twice in the same mapping, Line and Previous count from one
+type duplicateKeyError struct {
+	Key      string
+	Line     int
+	Previous int
+}
+
+func (e *duplicateKeyError) Error() string {
+	return fmt.Sprintf("yaml: line %d: key %s is already set at line %d", e.Line, e.Key, e.Previous)
+}
+
+// fromYAML converts a yaml node into values encoding/json marshals in order
+// a mapping key set twice in the same mapping is a duplicateKeyError, keys brought in by a << merge
+// are only set when the mapping does not declare them itself
+func fromYAML(n *yaml.Node) (interface{}, error) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, nil
+		}
+		return fromYAML(n.Content[0])
+	case yaml.AliasNode:
+		return fromYAML(n.Alias)
+	case yaml.MappingNode:
+		var o = newOrdered()
+		var lines = make(map[string]int)
+		var merges []*yaml.Node
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			if key.Tag == "!!merge" {
+				merges = append(merges, value)
+				continue
+			}
+			if previous, ok := lines[key.Value]; ok {
+				return nil, &duplicateKeyError{Key: key.Value, Line: key.Line, Previous: previous}
+			}
+			lines[key.Value] = key.Line
+			v, err := fromYAML(value)
+			if err != nil {
+				return nil, err
+			}
+			o.set(key.Value, v)
+		}
+		for _, merge := range merges {
+			if err := mergeYAML(o, merge); err != nil {
+				return nil, err
+			}
+		}
+		return o, nil
+	case yaml.SequenceNode:
+		var list = make([]interface{}, len(n.Content))
+		for i, item := range n.Content {
+			v, err := fromYAML(item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = v
+		}
+		return list, nil
+	}
+	var v interface{}
+	if err := n.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// mergeYAML sets the keys of the mappings a << merge key refers to that o does not hold yet
+func mergeYAML(o *ordered, n *yaml.Node) error {
+	if n.Kind == yaml.SequenceNode {
+		for _, item := range n.Content {
+			if err := mergeYAML(o, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	v, err := fromYAML(n)
+	if err != nil {
+		return err
+	}
+	merged, ok := v.(*ordered)
+	if !ok {
+		return fmt.Errorf("yaml: line %d: map merge requires map or sequence of maps as the value", n.Line)
+	}
+	for _, key := range merged.keys {
+		if _, ok := o.values[key]; !ok {
+			o.set(key, merged.values[key])
+		}
+	}
+	return nil
+}
+
+// decodeOrdered decodes json into ordered objects, lists and scalar values
+// numbers are kept as json.Number so they are written back unchanged
+func decodeOrdered(j []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(j))
+	dec.UseNumber()
+	return decodeOrderedValue(dec)
+}
+
+func decodeOrderedValue(dec *json.Decoder) (interface{}, error) {
+	t, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t {
+	case json.Delim('{'):
+		var o = newOrdered()
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrderedValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			o.set(key.(string), value)
+		}
+		_, err = dec.Token()
+		return o, err
+	case json.Delim('['):
+		var list = []interface{}{}
+		for dec.More() {
+			value, err := decodeOrderedValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err = dec.Token()
+		return list, err
+	}
+	return t, nil
+}
+
+// jsonToYAML converts a json document to yaml, keeping the order of the object keys
+func jsonToYAML(j []byte) ([]byte, error) {
+	v, err := decodeOrdered(j)
+	if err != nil {
+		return nil, err
+	}
+	n, err := toYAML(v)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(n); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// toYAML converts a value returned by decodeOrdered into a yaml node written in order
+func toYAML(v interface{}) (*yaml.Node, error) {
+	switch t := v.(type) {
+	case *ordered:
+		var n = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, key := range t.keys {
+			k, err := toYAML(key)
+			if err != nil {
+				return nil, err
+			}
+			value, err := toYAML(t.values[key])
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, k, value)
+		}
+		return n, nil
+	case []interface{}:
+		var n = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range t {
+			value, err := toYAML(item)
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, value)
+		}
+		return n, nil
+	case json.Number:
+		if i, err := strconv.ParseInt(string(t), 10, 64); err == nil {
+			v = i
+		} else {
+			v, _ = strconv.ParseFloat(string(t), 64)
+		}
+	}
+	var n = new(yaml.Node)
+	if err := n.Encode(v); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sfodje/swagson/specs"
+)
+
+func Test_yamlToJSON(t *testing.T) {
+	j, err := yamlToJSON([]byte("zeta: 1\nalpha:\n    200: ok\n    x-b: [2, 1]\n    x-a: true\n"))
+	expected := `{"zeta":1,"alpha":{"200":"ok","x-b":[2,1],"x-a":true}}`
+	if err == nil && string(j) == expected {
+		t.Log("yamlToJSON(yaml) passed.")
+	} else {
+		t.Log(err, string(j))
+		t.Error("yamlToJSON(yaml) failed.")
+	}
+
+	y, err := jsonToYAML(j)
+	if err == nil && string(y) == "zeta: 1\nalpha:\n  \"200\": ok\n  x-b:\n    - 2\n    - 1\n  x-a: true\n" {
+		t.Log("jsonToYAML(json) passed.")
+	} else {
+		t.Log(err, string(y))
+		t.Error("jsonToYAML(json) failed.")
+	}
+
+	j, err = yamlToJSON([]byte("ok: &ok\n    Description: ok\n    x-a: 1\nmoved:\n    <<: *ok\n    Description: moved\n"))
+	expected = `{"ok":{"Description":"ok","x-a":1},"moved":{"Description":"moved","x-a":1}}`
+	if err == nil && string(j) == expected {
+		t.Log("yamlToJSON(anchors and merge keys) passed.")
+	} else {
+		t.Log(err, string(j))
+		t.Error("yamlToJSON(anchors and merge keys) failed.")
+	}
+}
+
+func Test_extensions(t *testing.T) {
+	var swagDoc = new(specs.SwagDoc)
+	docs := []markupDoc{
+		{Node: specs.APIROUTE, File: "get.go", Line: 2, Column: 1, Text: `/pet:
+    x-path: path
+    Get:
+        OperationId: findPets
+        x-amazon-apigateway-integration:
+            type: http_proxy
+            uri: "http://backend/pet"
+            httpMethod: GET
+        x-amazon-apigateway-auth:
+            type: none
+        Responses:
+            200:
+                Description: ok
+                x-response: 1`}}
+	if err := handleRoute(swagDoc, docs); err != nil {
+		t.Fatal(err)
+	}
+
+	get := swagDoc.Paths.Paths["/pet"].Get
+	if len(get.Extensions) == 2 && get.Extensions[0].Key == "x-amazon-apigateway-integration" && get.Extensions[1].Key == "x-amazon-apigateway-auth" {
+		t.Log("handleRoute(swagDoc, extensions) passed.")
+	} else {
+		t.Log(get.Extensions)
+		t.Error("handleRoute(swagDoc, extensions) failed.")
+	}
+
+	j, err := DocToJson(swagDoc.Paths)
+	expected := `{"/pet":{"get":{"operationId":"findPets","responses":{"200":{"description":"ok","x-response":1}},` +
+		`"x-amazon-apigateway-integration":{"type":"http_proxy","uri":"http://backend/pet","httpMethod":"GET"},` +
+		`"x-amazon-apigateway-auth":{"type":"none"}},"x-path":"path"}}`
+	if err == nil && string(*j) == expected {
+		t.Log("DocToJson(extensions) passed.")
+	} else {
+		t.Log(err, string(*j))
+		t.Error("DocToJson(extensions) failed.")
+	}
+
+	y, err := DocToYaml(swagDoc.Paths)
+	if err == nil && strings.Contains(string(*y), "x-amazon-apigateway-integration:\n      type: http_proxy\n      uri: http://backend/pet\n      httpMethod: GET\n") {
+		t.Log("DocToYaml(extensions) passed.")
+	} else {
+		t.Log(err, string(*y))
+		t.Error("DocToYaml(extensions) failed.")
+	}
+
+	swagDoc.Swagger = "2.0"
+	swagDoc.Info = &specs.SwagInfo{Title: "pets", Version: "1.0.0", Extensions: specs.Extensions{{Key: "x-logo", Value: []byte(`"logo.png"`)}}}
+	doc, err := SwagDocToOpenAPI3(swagDoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, logo := doc.Info.Extensions.Get("x-logo")
+	_, auth := doc.Paths.Paths["/pet"].Get.Extensions.Get("x-amazon-apigateway-auth")
+	_, response := doc.Paths.Paths["/pet"].Get.Responses.Responses["200"].Extensions.Get("x-response")
+	if logo && auth && response {
+		t.Log("SwagDocToOpenAPI3(extensions) passed.")
+	} else {
+		t.Error("SwagDocToOpenAPI3(extensions) failed.")
+	}
+}
+
+func Test_duplicateKeys(t *testing.T) {
+	var swagDoc specs.SwagDoc
+	doc := markupDoc{Node: specs.APIMETA, File: "meta.go", Line: 2, Column: 1, Text: "Swagger: \"2.0\"\nInfo:\n    Title: t\n    Version: \"1.0\"\n    Title: u"}
+	err := doc.decode(&swagDoc)
+	if err != nil && err.Error() == "meta.go:6:1: api:meta: key Title is already set at line 4" {
+		t.Log("decode(duplicate meta key) passed.")
+	} else {
+		t.Log(err)
+		t.Error("decode(duplicate meta key) failed.")
+	}
+
+	var ops map[string]specs.SwagOperation
+	doc = markupDoc{Node: specs.APIROUTE, File: "pet.go", Line: 10, Column: 1, Text: "get:\n    summary: a\n    summary: b\npost:\n    summary: a"}
+	err = doc.decode(&ops)
+	if err != nil && err.Error() == "pet.go:12:1: api:route: key summary is already set at line 11" {
+		t.Log("decode(duplicate route key) passed.")
+	} else {
+		t.Log(err)
+		t.Error("decode(duplicate route key) failed.")
+	}
+}
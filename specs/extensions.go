@@ -0,0 +1,144 @@
+package specs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// Extension is a vendor extension of a spec object, a key starting with x- along with its json value
+type Extension struct {
+	Key   string
+	Value json.RawMessage
+}
+
+// Extensions holds the vendor extensions of a spec object in the order they were declared
+// they are read from and written back to the x- prefixed keys of the object
+type Extensions []Extension
+
+// Get returns the value of the extension with the given key
+func (e Extensions) Get(key string) (json.RawMessage, bool) {
+	for _, ext := range e {
+		if ext.Key == key {
+			return ext.Value, true
+		}
+	}
+	return nil, false
+}
+
+// isExtension reports whether an object key is a vendor extension
+func isExtension(key string) bool {
+	return strings.HasPrefix(strings.ToLower(key), "x-")
+}
+
+// unmarshalExtensible unmarshals data into v, the spec object o converted to a type without json methods,
+// and collects the x- prefixed keys of data into ext in the order they appear
+func unmarshalExtensible(data []byte, o interface{}, v interface{}, ext *Extensions) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return namedError(err, o, v)
+	}
+	*ext = nil
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return err
+	}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		if key := t.(string); isExtension(key) {
+			*ext = append(*ext, Extension{Key: key, Value: value})
+		}
+	}
+	return nil
+}
+
+// unmarshalExtensibleMap unmarshals the keys of data that are not vendor extensions into m, a pointer to a map of spec objects,
+// and collects the x- prefixed keys of data into ext in the order they appear
+func unmarshalExtensibleMap(data []byte, m interface{}, ext *Extensions) error {
+	*ext = nil
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		// let encoding/json report anything but an object
+		return json.Unmarshal(data, m)
+	}
+	var fields = bytes.NewBufferString("{")
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		key := t.(string)
+		if isExtension(key) {
+			*ext = append(*ext, Extension{Key: key, Value: value})
+			continue
+		}
+		if fields.Len() > 1 {
+			fields.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		fields.Write(name)
+		fields.WriteByte(':')
+		fields.Write(value)
+	}
+	fields.WriteByte('}')
+	return json.Unmarshal(fields.Bytes(), m)
+}
+
+// namedError replaces the type without json methods v in a json type error by the spec object o it was converted from,
+// so the error names the spec object rather than the conversion
+func namedError(err error, o interface{}, v interface{}) error {
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		return err
+	}
+	named, plain := reflect.TypeOf(o).Elem(), reflect.TypeOf(v).Elem()
+	if typeErr.Type == plain {
+		typeErr.Type = named
+	}
+	if typeErr.Struct == plain.Name() {
+		typeErr.Struct = named.Name()
+	}
+	return err
+}
+
+// marshalExtensible marshals v, a spec object converted to a type without json methods or a map of spec objects,
+// and appends the extensions after its fields
+func marshalExtensible(v interface{}, ext Extensions) ([]byte, error) {
+	j, err := json.Marshal(v)
+	if err == nil && string(j) == "null" {
+		// a nil map of spec objects is still written as an object
+		j = []byte("{}")
+	}
+	if err != nil || len(ext) == 0 {
+		return j, err
+	}
+	var buf = bytes.NewBuffer(j[:len(j)-1])
+	for _, e := range ext {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(e.Key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		if err := json.Compact(buf, e.Value); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,307 @@
+package specs
+
+// The spec objects keep their vendor extensions through the json methods below,
+// each converts the object to a type without methods to reuse the default encoding of its fields,
+// the paths and responses objects encode their map beside the extensions instead.
+
+func (o SwagDoc) MarshalJSON() ([]byte, error) {
+	type plain SwagDoc
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *SwagDoc) UnmarshalJSON(data []byte) error {
+	type plain SwagDoc
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o SwagInfo) MarshalJSON() ([]byte, error) {
+	type plain SwagInfo
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *SwagInfo) UnmarshalJSON(data []byte) error {
+	type plain SwagInfo
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o SwagContact) MarshalJSON() ([]byte, error) {
+	type plain SwagContact
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *SwagContact) UnmarshalJSON(data []byte) error {
+	type plain SwagContact
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o SwagLicense) MarshalJSON() ([]byte, error) {
+	type plain SwagLicense
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *SwagLicense) UnmarshalJSON(data []byte) error {
+	type plain SwagLicense
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o SwagPaths) MarshalJSON() ([]byte, error) {
+	return marshalExtensible(o.Paths, o.Extensions)
+}
+
+func (o *SwagPaths) UnmarshalJSON(data []byte) error {
+	return unmarshalExtensibleMap(data, &o.Paths, &o.Extensions)
+}
+
+func (o SwagPath) MarshalJSON() ([]byte, error) {
+	type plain SwagPath
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *SwagPath) UnmarshalJSON(data []byte) error {
+	type plain SwagPath
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o SwagOperation) MarshalJSON() ([]byte, error) {
+	type plain SwagOperation
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *SwagOperation) UnmarshalJSON(data []byte) error {
+	type plain SwagOperation
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o SwagParam) MarshalJSON() ([]byte, error) {
+	type plain SwagParam
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *SwagParam) UnmarshalJSON(data []byte) error {
+	type plain SwagParam
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o SwagResponses) MarshalJSON() ([]byte, error) {
+	return marshalExtensible(o.Responses, o.Extensions)
+}
+
+func (o *SwagResponses) UnmarshalJSON(data []byte) error {
+	return unmarshalExtensibleMap(data, &o.Responses, &o.Extensions)
+}
+
+func (o SwagResponse) MarshalJSON() ([]byte, error) {
+	type plain SwagResponse
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *SwagResponse) UnmarshalJSON(data []byte) error {
+	type plain SwagResponse
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o SwagHeader) MarshalJSON() ([]byte, error) {
+	type plain SwagHeader
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *SwagHeader) UnmarshalJSON(data []byte) error {
+	type plain SwagHeader
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o SwagSchema) MarshalJSON() ([]byte, error) {
+	type plain SwagSchema
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *SwagSchema) UnmarshalJSON(data []byte) error {
+	type plain SwagSchema
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o SwagXml) MarshalJSON() ([]byte, error) {
+	type plain SwagXml
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *SwagXml) UnmarshalJSON(data []byte) error {
+	type plain SwagXml
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o SwagItems) MarshalJSON() ([]byte, error) {
+	type plain SwagItems
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *SwagItems) UnmarshalJSON(data []byte) error {
+	type plain SwagItems
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o SwagSecDef) MarshalJSON() ([]byte, error) {
+	type plain SwagSecDef
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *SwagSecDef) UnmarshalJSON(data []byte) error {
+	type plain SwagSecDef
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o SwagTag) MarshalJSON() ([]byte, error) {
+	type plain SwagTag
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *SwagTag) UnmarshalJSON(data []byte) error {
+	type plain SwagTag
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o SwagExtDoc) MarshalJSON() ([]byte, error) {
+	type plain SwagExtDoc
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *SwagExtDoc) UnmarshalJSON(data []byte) error {
+	type plain SwagExtDoc
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o OpenAPIDoc) MarshalJSON() ([]byte, error) {
+	type plain OpenAPIDoc
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *OpenAPIDoc) UnmarshalJSON(data []byte) error {
+	type plain OpenAPIDoc
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o OpenAPIServer) MarshalJSON() ([]byte, error) {
+	type plain OpenAPIServer
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *OpenAPIServer) UnmarshalJSON(data []byte) error {
+	type plain OpenAPIServer
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o OpenAPIPaths) MarshalJSON() ([]byte, error) {
+	return marshalExtensible(o.Paths, o.Extensions)
+}
+
+func (o *OpenAPIPaths) UnmarshalJSON(data []byte) error {
+	return unmarshalExtensibleMap(data, &o.Paths, &o.Extensions)
+}
+
+func (o OpenAPIPathItem) MarshalJSON() ([]byte, error) {
+	type plain OpenAPIPathItem
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *OpenAPIPathItem) UnmarshalJSON(data []byte) error {
+	type plain OpenAPIPathItem
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o OpenAPIOperation) MarshalJSON() ([]byte, error) {
+	type plain OpenAPIOperation
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *OpenAPIOperation) UnmarshalJSON(data []byte) error {
+	type plain OpenAPIOperation
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o OpenAPIParam) MarshalJSON() ([]byte, error) {
+	type plain OpenAPIParam
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *OpenAPIParam) UnmarshalJSON(data []byte) error {
+	type plain OpenAPIParam
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o OpenAPIRequestBody) MarshalJSON() ([]byte, error) {
+	type plain OpenAPIRequestBody
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *OpenAPIRequestBody) UnmarshalJSON(data []byte) error {
+	type plain OpenAPIRequestBody
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o OpenAPIMediaType) MarshalJSON() ([]byte, error) {
+	type plain OpenAPIMediaType
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *OpenAPIMediaType) UnmarshalJSON(data []byte) error {
+	type plain OpenAPIMediaType
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o OpenAPIResponses) MarshalJSON() ([]byte, error) {
+	return marshalExtensible(o.Responses, o.Extensions)
+}
+
+func (o *OpenAPIResponses) UnmarshalJSON(data []byte) error {
+	return unmarshalExtensibleMap(data, &o.Responses, &o.Extensions)
+}
+
+func (o OpenAPIResponse) MarshalJSON() ([]byte, error) {
+	type plain OpenAPIResponse
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *OpenAPIResponse) UnmarshalJSON(data []byte) error {
+	type plain OpenAPIResponse
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o OpenAPIHeader) MarshalJSON() ([]byte, error) {
+	type plain OpenAPIHeader
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *OpenAPIHeader) UnmarshalJSON(data []byte) error {
+	type plain OpenAPIHeader
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o OpenAPISecScheme) MarshalJSON() ([]byte, error) {
+	type plain OpenAPISecScheme
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *OpenAPISecScheme) UnmarshalJSON(data []byte) error {
+	type plain OpenAPISecScheme
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o OpenAPIOAuthFlows) MarshalJSON() ([]byte, error) {
+	type plain OpenAPIOAuthFlows
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *OpenAPIOAuthFlows) UnmarshalJSON(data []byte) error {
+	type plain OpenAPIOAuthFlows
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
+
+func (o OpenAPIOAuthFlow) MarshalJSON() ([]byte, error) {
+	type plain OpenAPIOAuthFlow
+	return marshalExtensible(plain(o), o.Extensions)
+}
+
+func (o *OpenAPIOAuthFlow) UnmarshalJSON(data []byte) error {
+	type plain OpenAPIOAuthFlow
+	return unmarshalExtensible(data, o, (*plain)(o), &o.Extensions)
+}
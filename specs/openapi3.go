@@ -0,0 +1,138 @@
+package specs
+
+// OpenAPIDoc is the root of an OpenAPI 3.0 document
+// schemas, info, tags and external docs are shared with the swagger 2.0 model
+type OpenAPIDoc struct {
+	OpenAPI      string                 `json:"openapi"`
+	Info         *SwagInfo              `json:"info"`
+	Servers      *[]OpenAPIServer       `json:"servers,omitempty"`
+	Paths        *OpenAPIPaths          `json:"paths"`
+	Components   *OpenAPIComponents     `json:"components,omitempty"`
+	Security     *[]map[string][]string `json:"security,omitempty"`
+	Tags         *[]SwagTag             `json:"tags,omitempty"`
+	ExternalDocs *SwagExtDoc            `json:"externalDocs,omitempty"`
+	Extensions   Extensions             `json:"-"`
+}
+
+type OpenAPIServer struct {
+	Url         string     `json:"url"`
+	Description string     `json:"description,omitempty"`
+	Extensions  Extensions `json:"-"`
+}
+
+type OpenAPIComponents struct {
+	Schemas         *map[string]SwagSchema         `json:"schemas,omitempty"`
+	Responses       *map[string]OpenAPIResponse    `json:"responses,omitempty"`
+	Parameters      *map[string]OpenAPIParam       `json:"parameters,omitempty"`
+	RequestBodies   *map[string]OpenAPIRequestBody `json:"requestBodies,omitempty"`
+	SecuritySchemes *map[string]OpenAPISecScheme   `json:"securitySchemes,omitempty"`
+}
+
+// OpenAPIPaths is the paths object, the path items by path along with the vendor extensions beside them
+type OpenAPIPaths struct {
+	Paths      map[string]OpenAPIPathItem
+	Extensions Extensions
+}
+
+type OpenAPIPathItem struct {
+	Ref         string            `json:"$ref,omitempty"`
+	Summary     string            `json:"summary,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Get         *OpenAPIOperation `json:"get,omitempty"`
+	Put         *OpenAPIOperation `json:"put,omitempty"`
+	Post        *OpenAPIOperation `json:"post,omitempty"`
+	Delete      *OpenAPIOperation `json:"delete,omitempty"`
+	Options     *OpenAPIOperation `json:"options,omitempty"`
+	Head        *OpenAPIOperation `json:"head,omitempty"`
+	Patch       *OpenAPIOperation `json:"patch,omitempty"`
+	Parameters  *[]OpenAPIParam   `json:"parameters,omitempty"`
+	Extensions  Extensions        `json:"-"`
+}
+
+type OpenAPIOperation struct {
+	Tags         *[]string              `json:"tags,omitempty"`
+	Summary      string                 `json:"summary,omitempty"`
+	Description  *string                `json:"description,omitempty"`
+	ExternalDocs *SwagExtDoc            `json:"externalDocs,omitempty"`
+	OperationId  string                 `json:"operationId,omitempty"`
+	Parameters   *[]OpenAPIParam        `json:"parameters,omitempty"`
+	RequestBody  *OpenAPIRequestBody    `json:"requestBody,omitempty"`
+	Responses    *OpenAPIResponses      `json:"responses"`
+	Deprecated   bool                   `json:"deprecated,omitempty"`
+	Security     *[]map[string][]string `json:"security,omitempty"`
+	Servers      *[]OpenAPIServer       `json:"servers,omitempty"`
+	Extensions   Extensions             `json:"-"`
+}
+
+// OpenAPIResponses is the responses object of an operation, the responses by status code or default
+// along with the vendor extensions beside them
+type OpenAPIResponses struct {
+	Responses  map[string]OpenAPIResponse
+	Extensions Extensions
+}
+
+type OpenAPIParam struct {
+	Ref             string      `json:"$ref,omitempty"`
+	Name            string      `json:"name,omitempty"`
+	In              string      `json:"in,omitempty"`
+	Description     string      `json:"description,omitempty"`
+	Required        *bool       `json:"required,omitempty"`
+	AllowEmptyValue bool        `json:"allowEmptyValue,omitempty"`
+	Style           string      `json:"style,omitempty"`
+	Explode         *bool       `json:"explode,omitempty"`
+	Schema          *SwagSchema `json:"schema,omitempty"`
+	Extensions      Extensions  `json:"-"`
+}
+
+type OpenAPIRequestBody struct {
+	Ref         string                       `json:"$ref,omitempty"`
+	Description string                       `json:"description,omitempty"`
+	Content     *map[string]OpenAPIMediaType `json:"content,omitempty"`
+	Required    bool                         `json:"required,omitempty"`
+	Extensions  Extensions                   `json:"-"`
+}
+
+type OpenAPIMediaType struct {
+	Schema     *SwagSchema `json:"schema,omitempty"`
+	Example    interface{} `json:"example,omitempty"`
+	Extensions Extensions  `json:"-"`
+}
+
+type OpenAPIResponse struct {
+	Ref         string                       `json:"$ref,omitempty"`
+	Description string                       `json:"description,omitempty"`
+	Headers     *map[string]OpenAPIHeader    `json:"headers,omitempty"`
+	Content     *map[string]OpenAPIMediaType `json:"content,omitempty"`
+	Extensions  Extensions                   `json:"-"`
+}
+
+type OpenAPIHeader struct {
+	Description string      `json:"description,omitempty"`
+	Schema      *SwagSchema `json:"schema,omitempty"`
+	Extensions  Extensions  `json:"-"`
+}
+
+type OpenAPISecScheme struct {
+	Type        string             `json:"type"`
+	Description string             `json:"description,omitempty"`
+	Name        string             `json:"name,omitempty"`
+	In          string             `json:"in,omitempty"`
+	Scheme      string             `json:"scheme,omitempty"`
+	Flows       *OpenAPIOAuthFlows `json:"flows,omitempty"`
+	Extensions  Extensions         `json:"-"`
+}
+
+type OpenAPIOAuthFlows struct {
+	Implicit          *OpenAPIOAuthFlow `json:"implicit,omitempty"`
+	Password          *OpenAPIOAuthFlow `json:"password,omitempty"`
+	ClientCredentials *OpenAPIOAuthFlow `json:"clientCredentials,omitempty"`
+	AuthorizationCode *OpenAPIOAuthFlow `json:"authorizationCode,omitempty"`
+	Extensions        Extensions        `json:"-"`
+}
+
+type OpenAPIOAuthFlow struct {
+	AuthorizationUrl string             `json:"authorizationUrl,omitempty"`
+	TokenUrl         string             `json:"tokenUrl,omitempty"`
+	Scopes           *map[string]string `json:"scopes"`
+	Extensions       Extensions         `json:"-"`
+}
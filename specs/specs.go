@@ -1,5 +1,11 @@
 package specs
 
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
 // MarkupNode is an enum of all the markup nodes to parse for
 type MarkupNode int
 
@@ -7,9 +13,12 @@ const (
 	APIMETA MarkupNode = iota
 	APIROUTE
 	APIMODEL
+	APIPARAMETER
+	APIRESPONSE
+	APISECURITYDEFINITION
 )
 
-var markupNodes = [...]string{"api:meta", "api:route", "api:model"}
+var markupNodes = [...]string{"api:meta", "api:route", "api:model", "api:parameter", "api:response", "api:securityDefinition"}
 
 func (m MarkupNode) String() string {
 	return markupNodes[m]
@@ -51,14 +60,34 @@ const (
 	SSV
 	TSV
 	PIPES
+	MULTI
 )
 
-var collectionFormats = [...]string{"csv", "ssv", "tsv", "pipes"}
+var collectionFormats = [...]string{"csv", "ssv", "tsv", "pipes", "multi"}
 
 func (c CollectionFormat) String() string {
 	return collectionFormats[c]
 }
 
+// MarshalText writes the collection format by name, as it appears in the document
+func (c CollectionFormat) MarshalText() ([]byte, error) {
+	if int(c) < 0 || int(c) >= len(collectionFormats) {
+		return nil, fmt.Errorf("invalid collection format %d", int(c))
+	}
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText reads the collection format from its name
+func (c *CollectionFormat) UnmarshalText(text []byte) error {
+	for i, name := range collectionFormats {
+		if name == string(text) {
+			*c = CollectionFormat(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid collection format %q, expected one of %s", text, strings.Join(collectionFormats[:], ", "))
+}
+
 type ParamLocation int
 
 const (
@@ -69,7 +98,7 @@ const (
 	BODY
 )
 
-var paramLocation = [...]string{"query", "header", "path", "formdata", "body"}
+var paramLocation = [...]string{"query", "header", "path", "formData", "body"}
 
 func (p ParamLocation) String() string {
 	return paramLocation[p]
@@ -83,14 +112,15 @@ type SwagDoc struct {
 	Schemes             *[]string                `json:"schemes,omitempty"`
 	Consumes            *[]string                `json:"consumes,omitempty"`
 	Produces            *[]string                `json:"produces,omitempty"`
-	Paths               *map[string]SwagPath     `json:"paths"`
+	Paths               *SwagPaths               `json:"paths"`
 	Definitions         *map[string]SwagSchema   `json:"definitions,omitempty"`
 	Parameters          *map[string]SwagParam    `json:"parameters,omitempty"`
 	Responses           *map[string]SwagResponse `json:"responses,omitempty"`
 	SecurityDefinitions *map[string]SwagSecDef   `json:"securityDefinitions,omitempty"`
-	Security            *map[string][]string     `json:"security,omitempty"`
+	Security            *[]map[string][]string   `json:"security,omitempty"`
 	Tags                *[]SwagTag               `json:"tags,omitempty"`
 	ExternalDocs        *SwagExtDoc              `json:"externalDocs,omitempty"`
+	Extensions          Extensions               `json:"-"`
 }
 
 type SwagInfo struct {
@@ -100,6 +130,7 @@ type SwagInfo struct {
 	Contact        *SwagContact `json:"contact,omitempty"`
 	License        *SwagLicense `json:"license,omitempty"`
 	Version        string       `json:"version"`
+	Extensions     Extensions   `json:"-"`
 }
 
 type externalReference struct {
@@ -109,11 +140,19 @@ type externalReference struct {
 
 type SwagContact struct {
 	externalReference
-	Email string `json:"email, omitempty"`
+	Email      string     `json:"email,omitempty"`
+	Extensions Extensions `json:"-"`
 }
 
 type SwagLicense struct {
 	externalReference
+	Extensions Extensions `json:"-"`
+}
+
+// SwagPaths is the paths object, the path items by path along with the vendor extensions beside them
+type SwagPaths struct {
+	Paths      map[string]SwagPath
+	Extensions Extensions
 }
 
 type SwagPath struct {
@@ -126,28 +165,38 @@ type SwagPath struct {
 	Head       *SwagOperation `json:"head,omitempty"`
 	Patch      *SwagOperation `json:"patch,omitempty"`
 	Parameters *[]SwagParam   `json:"parameters,omitempty"`
+	Extensions Extensions     `json:"-"`
 }
 
 type SwagOperation struct {
-	Tags         *[]string                `json:"tags,omitempty"`
-	Summary      string                   `json:"summary,omitempty"`
-	Description  string                   `json:"description,omitempty"`
-	ExternalDocs *SwagExtDoc              `json:"externalDocs,omitempty"`
-	OperationId  string                   `json:"operationId,omitempty"`
-	Consumes     *[]string                `json:"consumes,omitempty"`
-	Produces     *[]string                `json:"produces,omitempty"`
-	Parameters   *[]SwagParam             `json:"parameters,omitempty"`
-	Responses    *map[string]SwagResponse `json:"responses"`
-	Schemes      *[]string                `json:"schemes,omitempty"`
-	Deprecated   *[]string                `json:"deprecated,omitempty"`
-	Security     *[]map[string][]string   `json:"security,omitempty"`
+	Tags         *[]string              `json:"tags,omitempty"`
+	Summary      string                 `json:"summary,omitempty"`
+	Description  *string                `json:"description,omitempty"`
+	ExternalDocs *SwagExtDoc            `json:"externalDocs,omitempty"`
+	OperationId  string                 `json:"operationId,omitempty"`
+	Consumes     *[]string              `json:"consumes,omitempty"`
+	Produces     *[]string              `json:"produces,omitempty"`
+	Parameters   *[]SwagParam           `json:"parameters,omitempty"`
+	Responses    *SwagResponses         `json:"responses"`
+	Schemes      *[]string              `json:"schemes,omitempty"`
+	Deprecated   bool                   `json:"deprecated,omitempty"`
+	Security     *[]map[string][]string `json:"security,omitempty"`
+	Extensions   Extensions             `json:"-"`
+}
+
+// SwagResponses is the responses object of an operation, the responses by status code or default
+// along with the vendor extensions beside them
+type SwagResponses struct {
+	Responses  map[string]SwagResponse
+	Extensions Extensions
 }
 
 type SwagParam struct {
-	Name             string            `json:"name"`
-	In               string            `json:"in"`
+	Ref              string            `json:"$ref,omitempty"`
+	Name             string            `json:"name,omitempty"`
+	In               string            `json:"in,omitempty"`
 	Description      string            `json:"description,omitempty"`
-	Required         bool              `json:"required,omitempty"`
+	Required         *bool             `json:"required,omitempty"`
 	Schema           *SwagSchema       `json:"schema,omitempty"`
 	Type             string            `json:"type,omitempty"`
 	Format           string            `json:"format,omitempty"`
@@ -155,9 +204,9 @@ type SwagParam struct {
 	Items            *SwagItems        `json:"items,omitempty"`
 	CollectionFormat *CollectionFormat `json:"collectionFormat,omitempty"`
 	Default          interface{}       `json:"default,omitempty"`
-	Maximum          int               `json:"maximum,omitempty"`
+	Maximum          *float64          `json:"maximum,omitempty"`
 	ExclusiveMaximum bool              `json:"exclusiveMaximum,omitempty"`
-	Minimum          int               `json:"minimum,omitempty"`
+	Minimum          *float64          `json:"minimum,omitempty"`
 	ExclusiveMinimum bool              `json:"exclusiveMinimum,omitempty"`
 	MaxLength        int               `json:"maxLength,omitempty"`
 	MinLength        int               `json:"minLength,omitempty"`
@@ -165,64 +214,151 @@ type SwagParam struct {
 	MaxItems         int               `json:"maxItems,omitempty"`
 	MinItems         int               `json:"minItems,omitempty"`
 	UniqueItems      bool              `json:"uniqueItems,omitempty"`
-	Enum             *[]string         `json:"enum,omitempty"`
-	MultipleOf       int               `json:"multipleOf,omitempty"`
+	Enum             *[]interface{}    `json:"enum,omitempty"`
+	MultipleOf       *float64          `json:"multipleOf,omitempty"`
+	Extensions       Extensions        `json:"-"`
 }
 
 type SwagResponse struct {
-	Description string                  `json:"description"`
-	Schema      *map[string]interface{} `json:"schema,omitempty"`
-	Headers     *map[string]SwagItems   `json:"headers,omitempty"`
-	Examples    *map[string]interface{} `json:"examples,omitempty"`
+	Ref         string                      `json:"$ref,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	Schema      *SwagSchema                 `json:"schema,omitempty"`
+	Headers     *map[string]SwagHeader      `json:"headers,omitempty"`
+	Examples    *map[string]json.RawMessage `json:"examples,omitempty"` // example values keyed by mime type, kept as written
+	Extensions  Extensions                  `json:"-"`
+}
+
+type SwagHeader struct {
+	Description      string            `json:"description,omitempty"`
+	Type             string            `json:"type,omitempty"`
+	Format           string            `json:"format,omitempty"`
+	Items            *SwagItems        `json:"items,omitempty"` // required if type is Array
+	CollectionFormat *CollectionFormat `json:"collectionFormat,omitempty"`
+	Default          interface{}       `json:"default,omitempty"`
+	Maximum          *float64          `json:"maximum,omitempty"`
+	ExclusiveMaximum bool              `json:"exclusiveMaximum,omitempty"`
+	Minimum          *float64          `json:"minimum,omitempty"`
+	ExclusiveMinimum bool              `json:"exclusiveMinimum,omitempty"`
+	MaxLength        int               `json:"maxLength,omitempty"`
+	MinLength        int               `json:"minLength,omitempty"`
+	Pattern          string            `json:"pattern,omitempty"`
+	MaxItems         int               `json:"maxItems,omitempty"`
+	MinItems         int               `json:"minItems,omitempty"`
+	UniqueItems      bool              `json:"uniqueItems,omitempty"`
+	Enum             *[]interface{}    `json:"enum,omitempty"`
+	MultipleOf       *float64          `json:"multipleOf,omitempty"`
+	Extensions       Extensions        `json:"-"`
 }
 
 type SwagSchema struct {
-	Ref              string                 `json:"$ref,omitempty"`
-	Format           string                 `json:"format,omitempty"`
-	Title            string                 `json:"title,omitempty"`
-	Description      string                 `json:"description,omitempty"`
-	Default          string                 `json:"default,omitempty"`
-	MultipleOf       int                    `json:"multipleOf,omitempty"`
-	Maximum          int                    `json:"maximum,omitempty"`
-	ExclusiveMaximum int                    `json:"exclusiveMaximum,omitempty"`
-	Minimum          int                    `json:"minimum,omitempty"`
-	ExclusiveMinimum int                    `json:"exclusiveMinimum,omitempty"`
-	MaxLength        int                    `json:"maxlength,omitempty"`
-	MinLength        int                    `json:"minlength,omitempty"`
-	Pattern          string                 `json:"pattern,omitempty"`
-	MaxItems         int                    `json:"maxItems,omitempty"`
-	MinItems         int                    `json:"minItems,omitempty"`
-	UniqueItems      bool                   `json:"uniqueItems,omitempty"`
-	MaxProperties    int                    `json:"maxProperties,omitempty"`
-	MinProperties    int                    `json:"minProperties,omitempty"`
-	Required         *[]string              `json:"required,omitempty"`
-	Enum             *[]string              `json:"enum,omitempty"`
-	Type             string                 `json:"type,omitempty"`
-	Properties       *map[string]SwagSchema `json:"properties,omitempty"`
-	Xml              *SwagXml               `json:"xml,omitempty"`
-	Example          string                 `json:"example,omitempty"`
-	Items            *SwagItems             `json:"items,omitempty"` // required if type is Array
+	Ref                  string                 `json:"$ref,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Default              interface{}            `json:"default,omitempty"`
+	MultipleOf           *float64               `json:"multipleOf,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+	ExclusiveMaximum     bool                   `json:"exclusiveMaximum,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	ExclusiveMinimum     bool                   `json:"exclusiveMinimum,omitempty"`
+	MaxLength            int                    `json:"maxLength,omitempty"`
+	MinLength            int                    `json:"minLength,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	MaxItems             int                    `json:"maxItems,omitempty"`
+	MinItems             int                    `json:"minItems,omitempty"`
+	UniqueItems          bool                   `json:"uniqueItems,omitempty"`
+	MaxProperties        int                    `json:"maxProperties,omitempty"`
+	MinProperties        int                    `json:"minProperties,omitempty"`
+	Required             *[]string              `json:"required,omitempty"`
+	Enum                 *[]interface{}         `json:"enum,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Items                *SwagSchema            `json:"items,omitempty"` // required if type is Array
+	AllOf                *[]SwagSchema          `json:"allOf,omitempty"`
+	Properties           *map[string]SwagSchema `json:"properties,omitempty"`
+	AdditionalProperties *SwagSchemaOrBool      `json:"additionalProperties,omitempty"`
+	Discriminator        *SwagDiscriminator     `json:"discriminator,omitempty"`
+	ReadOnly             bool                   `json:"readOnly,omitempty"`
+	Xml                  *SwagXml               `json:"xml,omitempty"`
+	ExternalDocs         *SwagExtDoc            `json:"externalDocs,omitempty"`
+	Example              interface{}            `json:"example,omitempty"`
+	Extensions           Extensions             `json:"-"`
+}
+
+// SwagDiscriminator is the discriminator of a polymorphic schema
+// swagger 2.0 writes it as the name of the property, OpenAPI 3.0 as a Discriminator Object holding that name.
+type SwagDiscriminator struct {
+	PropertyName string             `json:"propertyName"`
+	Mapping      *map[string]string `json:"mapping,omitempty"`
+	// Object is set to write the discriminator as an OpenAPI 3.0 Discriminator Object
+	Object bool `json:"-"`
+}
+
+func (d SwagDiscriminator) MarshalJSON() ([]byte, error) {
+	if !d.Object {
+		return json.Marshal(d.PropertyName)
+	}
+	type object SwagDiscriminator
+	return json.Marshal(object(d))
+}
+
+func (d *SwagDiscriminator) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &d.PropertyName); err == nil {
+		d.Mapping, d.Object = nil, false
+		return nil
+	}
+	type object SwagDiscriminator
+	var o object
+	if err := json.Unmarshal(data, &o); err != nil {
+		return err
+	}
+	*d = SwagDiscriminator(o)
+	d.Object = true
+	return nil
+}
+
+// SwagSchemaOrBool is the value of additionalProperties, either a boolean or the schema of the additional properties
+type SwagSchemaOrBool struct {
+	Allows bool
+	Schema *SwagSchema
+}
+
+func (s SwagSchemaOrBool) MarshalJSON() ([]byte, error) {
+	if s.Schema != nil {
+		return json.Marshal(s.Schema)
+	}
+	return json.Marshal(s.Allows)
+}
+
+func (s *SwagSchemaOrBool) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &s.Allows); err == nil {
+		s.Schema = nil
+		return nil
+	}
+	s.Allows = true
+	s.Schema = new(SwagSchema)
+	return json.Unmarshal(data, s.Schema)
 }
 
 type SwagXml struct {
-	Name      string `json:"name,omitempty"`
-	Namespace string `json:"namespace,omitempty"`
-	Prefix    string `json:"prefix,omitempty"`
-	Attribute bool   `json:"attribute,omitempty"`
-	Wrapped   bool   `json:"wrapped,omitempty"`
+	Name       string     `json:"name,omitempty"`
+	Namespace  string     `json:"namespace,omitempty"`
+	Prefix     string     `json:"prefix,omitempty"`
+	Attribute  bool       `json:"attribute,omitempty"`
+	Wrapped    bool       `json:"wrapped,omitempty"`
+	Extensions Extensions `json:"-"`
 }
 
 type SwagItems struct {
 	Ref              string            `json:"$ref,omitempty"`
 	Description      string            `json:"description,omitempty"`
-	Type             string            `json:"type"`
+	Type             string            `json:"type,omitempty"`
 	Format           string            `json:"format,omitempty"`
 	Items            *SwagItems        `json:"items,omitempty"` // required if type is Array
 	CollectionFormat *CollectionFormat `json:"collectionFormat,omitempty"`
 	Default          interface{}       `json:"default,omitempty"`
-	Maximum          int               `json:"maximum,omitempty"`
+	Maximum          *float64          `json:"maximum,omitempty"`
 	ExclusiveMaximum bool              `json:"exclusiveMaximum,omitempty"`
-	Minimum          int               `json:"minimum,omitempty"`
+	Minimum          *float64          `json:"minimum,omitempty"`
 	ExclusiveMinimum bool              `json:"exclusiveMinimum,omitempty"`
 	MaxLength        int               `json:"maxLength,omitempty"`
 	MinLength        int               `json:"minLength,omitempty"`
@@ -230,8 +366,9 @@ type SwagItems struct {
 	MaxItems         int               `json:"maxItems,omitempty"`
 	MinItems         int               `json:"minItems,omitempty"`
 	UniqueItems      bool              `json:"uniqueItems,omitempty"`
-	Enum             *[]string         `json:"enum,omitempty"`
-	MultipleOf       int               `json:"multipleOf,omitempty"`
+	Enum             *[]interface{}    `json:"enum,omitempty"`
+	MultipleOf       *float64          `json:"multipleOf,omitempty"`
+	Extensions       Extensions        `json:"-"`
 }
 
 type SwagSecDef struct {
@@ -243,15 +380,18 @@ type SwagSecDef struct {
 	AuthorizationUrl string             `json:"authorizationUrl,omitempty"`
 	TokenUrl         string             `json:"tokenUrl,omitempty"`
 	Scopes           *map[string]string `json:"scopes,omitempty"`
+	Extensions       Extensions         `json:"-"`
 }
 
 type SwagTag struct {
 	Name         string      `json:"name"`
 	Description  string      `json:"description,omitempty"`
 	ExternalDocs *SwagExtDoc `json:"externalDocs,omitempty"`
+	Extensions   Extensions  `json:"-"`
 }
 
 type SwagExtDoc struct {
-	Description string `json:"description,omitempty"`
-	Url         string `json:"url,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Url         string     `json:"url,omitempty"`
+	Extensions  Extensions `json:"-"`
 }
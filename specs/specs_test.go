@@ -0,0 +1,264 @@
+package specs
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// roundTrip decodes a swagger document into the spec model and encodes it back
+func roundTrip(t *testing.T, source []byte) []byte {
+	var doc SwagDoc
+	if err := json.Unmarshal(source, &doc); err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(out, '\n')
+}
+
+func Test_petstoreRoundTrip(t *testing.T) {
+	source, err := os.ReadFile("testdata/petstore.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := roundTrip(t, source)
+	if *update {
+		if err := os.WriteFile("testdata/petstore.golden.json", out, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	golden, err := os.ReadFile("testdata/petstore.golden.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Equal(out, golden) {
+		t.Log("roundTrip(petstore.json) passed.")
+	} else {
+		t.Error("roundTrip(petstore.json) failed, run go test -update and review the golden file.")
+	}
+	if bytes.Equal(roundTrip(t, golden), golden) {
+		t.Log("roundTrip(petstore.golden.json) passed.")
+	} else {
+		t.Error("roundTrip(petstore.golden.json) failed.")
+	}
+
+	// the golden file has the keys in the order of the spec and numbers in their shortest form, every value must be kept
+	var before, after interface{}
+	if err := json.Unmarshal(source, &before); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(golden, &after); err != nil {
+		t.Fatal(err)
+	}
+	if diffs := differences(before, after, ""); len(diffs) == 0 {
+		t.Log("roundTrip(petstore.json) values passed.")
+	} else {
+		t.Log(diffs)
+		t.Error("roundTrip(petstore.json) values failed.")
+	}
+}
+
+// differences returns the paths of the values of before that are missing from, changed in or added to after
+func differences(before interface{}, after interface{}, path string) []string {
+	var diffs []string
+	switch b := before.(type) {
+	case map[string]interface{}:
+		a, ok := after.(map[string]interface{})
+		if !ok {
+			return []string{path}
+		}
+		for k, v := range b {
+			if _, ok := a[k]; !ok {
+				diffs = append(diffs, path+"/"+k)
+				continue
+			}
+			diffs = append(diffs, differences(v, a[k], path+"/"+k)...)
+		}
+		for k := range a {
+			if _, ok := b[k]; !ok {
+				diffs = append(diffs, path+"/"+k)
+			}
+		}
+	case []interface{}:
+		a, ok := after.([]interface{})
+		if !ok || len(a) != len(b) {
+			return []string{path}
+		}
+		for i := range b {
+			diffs = append(diffs, differences(b[i], a[i], fmt.Sprintf("%s/%d", path, i))...)
+		}
+	default:
+		if !reflect.DeepEqual(before, after) {
+			return []string{path}
+		}
+	}
+	sort.Strings(diffs)
+	return diffs
+}
+
+func Test_securityAndDeprecated(t *testing.T) {
+	var doc SwagDoc
+	source := `{"swagger":"2.0","paths":{"/pet":{"get":{"deprecated":true,"responses":{}}}},` +
+		`"security":[{"api_key":[]},{"petstore_auth":["write:pets","read:pets"]}]}`
+	err := json.Unmarshal([]byte(source), &doc)
+	if err == nil && len(*doc.Security) == 2 && doc.Paths.Paths["/pet"].Get.Deprecated {
+		t.Log("json.Unmarshal(security requirements, deprecated) passed.")
+	} else {
+		t.Log(err)
+		t.Error("json.Unmarshal(security requirements, deprecated) failed.")
+	}
+
+	out, err := json.Marshal(doc)
+	expected := `{"swagger":"2.0","info":null,"paths":{"/pet":{"get":{"responses":{},"deprecated":true}}},` +
+		`"security":[{"api_key":[]},{"petstore_auth":["write:pets","read:pets"]}]}`
+	if err == nil && string(out) == expected {
+		t.Log("json.Marshal(security requirements, deprecated) passed.")
+	} else {
+		t.Log(err, string(out))
+		t.Error("json.Marshal(security requirements, deprecated) failed.")
+	}
+
+	var format CollectionFormat
+	if err := json.Unmarshal([]byte(`"tabs"`), &format); err != nil {
+		t.Log("json.Unmarshal(invalid collection format) passed.")
+	} else {
+		t.Error("json.Unmarshal(invalid collection format) failed.")
+	}
+}
+
+func Test_enums(t *testing.T) {
+	var tests = []struct {
+		name   string
+		source string
+		v      interface{}
+	}{
+		{"param", `{"name":"status","in":"query","type":"integer","enum":[1,2,3]}`, new(SwagParam)},
+		{"header", `{"type":"number","enum":[0.5,1]}`, new(SwagHeader)},
+		{"items", `{"type":"boolean","enum":[true]}`, new(SwagItems)},
+	}
+	for _, test := range tests {
+		err := json.Unmarshal([]byte(test.source), test.v)
+		out, _ := json.Marshal(test.v)
+		if err == nil && string(out) == test.source {
+			t.Logf("json.Unmarshal(%s, enum) passed.", test.name)
+		} else {
+			t.Log(err, string(out))
+			t.Errorf("json.Unmarshal(%s, enum) failed.", test.name)
+		}
+	}
+}
+
+func Test_zeroBounds(t *testing.T) {
+	var schema SwagSchema
+	source := `{"multipleOf":0.5,"maximum":0,"minimum":0,"type":"number"}`
+	if err := json.Unmarshal([]byte(source), &schema); err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.Marshal(schema)
+	if err == nil && string(out) == source {
+		t.Log("json.Marshal(schema, zero bounds) passed.")
+	} else {
+		t.Log(err, string(out))
+		t.Error("json.Marshal(schema, zero bounds) failed.")
+	}
+
+	var param SwagParam
+	source = `{"name":"offset","in":"query","type":"integer","items":{"type":"integer","minimum":0},"minimum":0}`
+	if err := json.Unmarshal([]byte(source), &param); err != nil {
+		t.Fatal(err)
+	}
+	out, err = json.Marshal(param)
+	if err == nil && string(out) == source {
+		t.Log("json.Marshal(param, zero bounds) passed.")
+	} else {
+		t.Log(err, string(out))
+		t.Error("json.Marshal(param, zero bounds) failed.")
+	}
+
+	var header SwagHeader
+	source = `{"type":"integer","maximum":0}`
+	if err := json.Unmarshal([]byte(source), &header); err != nil {
+		t.Fatal(err)
+	}
+	out, err = json.Marshal(header)
+	if err == nil && string(out) == source {
+		t.Log("json.Marshal(header, zero bounds) passed.")
+	} else {
+		t.Log(err, string(out))
+		t.Error("json.Marshal(header, zero bounds) failed.")
+	}
+}
+
+func Test_zeroValues(t *testing.T) {
+	var op SwagOperation
+	source := `{"description":"","parameters":[{"name":"status","in":"query","required":false,"x-a":1}],"responses":{}}`
+	if err := json.Unmarshal([]byte(source), &op); err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.Marshal(op)
+	if err == nil && string(out) == source && len((*op.Parameters)[0].Extensions) == 1 {
+		t.Log("json.Marshal(operation, zero values) passed.")
+	} else {
+		t.Log(err, string(out))
+		t.Error("json.Marshal(operation, zero values) failed.")
+	}
+}
+
+func Test_pathsAndResponsesExtensions(t *testing.T) {
+	source := `{"swagger":"2.0","info":{"title":"pets","version":"1.0.0"},"paths":{"/pet":{"get":{"responses":{"200":{"description":"ok"},"x-rate-limit":10}}},"x-paths":{"a":1}}}`
+	var doc SwagDoc
+	err := json.Unmarshal([]byte(source), &doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.Marshal(doc)
+	_, limit := doc.Paths.Paths["/pet"].Get.Responses.Extensions.Get("x-rate-limit")
+	_, paths := doc.Paths.Extensions.Get("x-paths")
+	if err == nil && limit && paths && len(doc.Paths.Paths) == 1 && len(doc.Paths.Paths["/pet"].Get.Responses.Responses) == 1 && string(out) == source {
+		t.Log("roundTrip(paths and responses extensions) passed.")
+	} else {
+		t.Log(err, string(out))
+		t.Error("roundTrip(paths and responses extensions) failed.")
+	}
+
+	var empty = SwagDoc{Paths: &SwagPaths{}}
+	out, err = json.Marshal(empty)
+	if err == nil && bytes.Contains(out, []byte(`"paths":{}`)) {
+		t.Log("Marshal(nil paths) passed.")
+	} else {
+		t.Log(err, string(out))
+		t.Error("Marshal(nil paths) failed.")
+	}
+}
+
+func Test_decodeErrors(t *testing.T) {
+	var tests = []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"response", `{"get":{"responses":{"200":10}}}`, "json: cannot unmarshal number into Go value of type specs.SwagResponse"},
+		{"operation field", `{"get":{"summary":1}}`, "json: cannot unmarshal number into Go struct field SwagOperation.summary of type string"},
+	}
+	for _, test := range tests {
+		var path SwagPath
+		err := json.Unmarshal([]byte(test.source), &path)
+		if err != nil && err.Error() == test.want {
+			t.Log("Unmarshal(" + test.name + ") passed.")
+		} else {
+			t.Log(err)
+			t.Error("Unmarshal(" + test.name + ") failed.")
+		}
+	}
+}